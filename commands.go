@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	// Local packages
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+const (
+	ApplyCommand   string = "apply"
+	PlanCommand    string = "plan"
+	InspectCommand string = "inspect"
+	KeysCommand    string = "keys"
+	DocsCommand    string = "docs"
+	SchemaCommand  string = "schema"
+	VerifyCommand  string = "verify"
+	RestoreCommand string = "restore"
+)
+
+// Command is a subcommand of the program, eg : env2js plan -strict
+type Command struct {
+	Name        string
+	Description string
+
+	// Flags registers the command-line flags of the command
+	Flags func(flags *flag.FlagSet, conf *CommandLineConfig)
+
+	// Run executes the command on the settings file located by the config
+	Run func(ctx context.Context, config *CommandLineConfig) error
+}
+
+// Commands lists the subcommands, the first one being the default command.
+var Commands = []Command{
+	{
+		Name:        ApplyCommand,
+		Description: "Apply the environment variables to the settings file (default)",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+			OutputFlags(flags, conf)
+			SourceFlags(flags, conf)
+			StrictFlags(flags, conf)
+			ValidationFlags(flags, conf)
+			SecretFlags(flags, conf)
+		},
+		Run: Apply,
+	},
+	{
+		Name:        PlanCommand,
+		Description: "Show the changes apply would make, without writing anything",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+			SourceFlags(flags, conf)
+			StrictFlags(flags, conf)
+			ValidationFlags(flags, conf)
+			SecretFlags(flags, conf)
+		},
+		Run: Plan,
+	},
+	{
+		Name:        InspectCommand,
+		Description: "Print the settings tree found in the settings file",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			SecretFlags(flags, conf)
+		},
+		Run: Inspect,
+	},
+	{
+		Name:        KeysCommand,
+		Description: "List every overridable environment variable with its default value",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+			flags.StringVar(&conf.Format, "format", env2js.KeysFormatEnv, "Output format : "+strings.Join(env2js.KeysFormats, ", "))
+			flags.StringVar(&conf.Name, "name", "", "Name of the ConfigMap (default derived from the settings variable name)")
+			SecretFlags(flags, conf)
+		},
+		Run: Keys,
+	},
+	{
+		Name:        DocsCommand,
+		Description: "Generate the reference documentation of the settings from their comments",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+			flags.StringVar(&conf.Format, "format", env2js.DocsFormatMarkdown, "Output format : "+strings.Join(env2js.DocsFormats, ", "))
+			SecretFlags(flags, conf)
+		},
+		Run: Docs,
+	},
+	{
+		Name:        SchemaCommand,
+		Description: "Generate the JSON Schema or the TypeScript declaration of the settings object",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+			flags.StringVar(&conf.Format, "format", env2js.SchemaFormatJSON, "Output format : "+strings.Join(env2js.SchemaFormats, ", "))
+		},
+		Run: GenerateSchema,
+	},
+	{
+		Name:        VerifyCommand,
+		Description: "Check that the settings file already reflects the environment variables",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			SourceFlags(flags, conf)
+			SecretFlags(flags, conf)
+		},
+		Run: Verify,
+	},
+	{
+		Name:        RestoreCommand,
+		Description: "Put the original settings file back, see -template",
+		Flags: func(flags *flag.FlagSet, conf *CommandLineConfig) {
+			LocationFlags(flags, conf)
+			TemplateFlags(flags, conf)
+		},
+		Run: Restore,
+	},
+}
+
+// FindCommand returns the subcommand with the given name, or nil.
+func FindCommand(name string) *Command {
+	index := slices.IndexFunc(Commands, func(command Command) bool { return command.Name == name })
+	if index < 0 {
+		return nil
+	}
+
+	return &Commands[index]
+}
+
+// PrintCommands writes the list of the subcommands for the usage message.
+func PrintCommands(w io.Writer) {
+	fmt.Fprint(w, "\nCommands:\n")
+	for _, command := range Commands {
+		fmt.Fprintf(w, "  %-8s %s\n", command.Name, command.Description)
+	}
+}
+
+// -folder / -prefix / -variable / -file
+func LocationFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.StringVar(&conf.Folder, "folder", "", "Folder that includes the configuration files (env "+SettingsFolderPathEnvKey+")")
+	flags.StringVar(&conf.Prefix, "prefix", "", "Configuration file name prefix (env "+SettingsFilePrefixEnvKey+")")
+	flags.StringVar(&conf.Variable, "variable", "", "Settings variable name to read inside the file (env "+SettingsVariableNameEnvKey+")")
+	flags.StringVar(&conf.File, "file", "", "Path of the configuration file, instead of searching the folder with the prefix")
+}
+
+// -template / -template-dir
+func TemplateFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.BoolVar(&conf.Template, "template", false, "Save the original settings file on first run and always apply overrides to it")
+	flags.StringVar(&conf.TemplateDir, "template-dir", "", "Directory of the original settings files (default next to the settings file, env "+env2js.SettingsTemplateDirEnvKey+")")
+}
+
+// -out-dir / -copy-assets
+func OutputFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.StringVar(&conf.OutputDir, "out-dir", "", "Directory where the updated settings file is written instead of in place (env "+env2js.SettingsOutputPathEnvKey+")")
+	flags.BoolVar(&conf.CopyAssets, "copy-assets", false, "Copy the untouched files of the settings folder into the output directory")
+}
+
+// -strict / -require
+func StrictFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.BoolVar(&conf.Strict, "strict", false, "Fail on unused environment variables or missing required keys")
+	flags.Var(&conf.RequiredKeys, "require", "Environment variable that must be set in strict mode, repeatable (env "+env2js.SettingsRequiredKeysEnvKey+", comma separated)")
+}
+
+// -env-file / -secrets-dir / -set / -precedence / -verbose / -interpolate / -transforms / -age-key-file
+func SourceFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.Var(&conf.EnvFiles, "env-file", ".env file read before the environment variables, repeatable (env "+env2js.SettingsEnvFilesEnvKey+", comma separated)")
+	flags.Var(&conf.SecretsDirs, "secrets-dir", "Directory of files named after the environment variables, repeatable (env "+env2js.SettingsSecretsDirsEnvKey+", comma separated)")
+	flags.Var(&conf.Sets, "set", "Value of an environment variable, repeatable, eg : AppSettings_API_apiRoot=https://example.com/api")
+	flags.Func("precedence", "Comma separated value sources, highest precedence first (env "+env2js.SettingsPrecedenceEnvKey+", default "+env2js.DefaultPrecedence.String()+")", func(value string) error {
+		conf.Precedence = env2js.SplitList(value)
+		return nil
+	})
+	flags.BoolVar(&conf.Verbose, "verbose", false, "Log the source of every applied value")
+	flags.BoolVar(&conf.Interpolate, "interpolate", false, "Expand the ${NAME} references of the values and of the string literals (env "+env2js.SettingsInterpolateEnvKey+")")
+	flags.StringVar(&conf.TransformsFile, "transforms", "", "JSON file of the transforms of the values by environment variable name, eg : {\"API_apiKey\": \"b64 | trim\"} (env "+env2js.SettingsTransformsPathEnvKey+")")
+	flags.StringVar(&conf.AgeKeyFile, "age-key-file", "", "File of the age identities decrypting the ENC[age,...] values and the encrypted .env files (env "+env2js.SettingsAgeKeyFileEnvKey+")")
+	VaultFlags(flags, conf)
+	KVFlags(flags, conf)
+}
+
+// -kv-addr / -kv-prefix / -kv-timeout / -kv-retries
+func KVFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.StringVar(&conf.KVAddr, "kv-addr", "", "Address of the Consul-compatible key-value store, enables the kv source (env "+env2js.SettingsKVAddrEnvKey+")")
+	flags.StringVar(&conf.KVPrefix, "kv-prefix", "", "Prefix of the keys holding the settings, eg : frontend/orders/ (env "+env2js.SettingsKVPrefixEnvKey+")")
+	flags.DurationVar(&conf.KVTimeout, "kv-timeout", env2js.DefaultKVTimeout, "Timeout of every request to the key-value store")
+	flags.IntVar(&conf.KVRetries, "kv-retries", env2js.DefaultKVRetries, "Number of retries of the failed requests to the key-value store")
+}
+
+// -vault-addr / -vault-mount / -vault-path
+func VaultFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.StringVar(&conf.VaultAddr, "vault-addr", "", "Address of the Vault server (env "+env2js.VaultAddrEnvKey+")")
+	flags.StringVar(&conf.VaultMount, "vault-mount", env2js.DefaultVaultMount, "Mount path of the Vault KV v2 secrets engine")
+	flags.StringVar(&conf.VaultPath, "vault-path", "", "Path of the Vault secret holding the settings, enables the vault source (env "+env2js.SettingsVaultPathEnvKey+")")
+}
+
+// -secret-pattern
+func SecretFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.Var(&conf.SecretPatterns, "secret-pattern", "Glob pattern of the environment variables whose values are masked, repeatable (env "+env2js.SettingsSecretPatternsEnvKey+", comma separated, default "+env2js.DefaultSecretPatterns.String()+")")
+}
+
+// -schema / -constraint
+func ValidationFlags(flags *flag.FlagSet, conf *CommandLineConfig) {
+	flags.StringVar(&conf.SchemaFile, "schema", "", "JSON Schema file the updated settings must satisfy (env "+env2js.SettingsSchemaPathEnvKey+")")
+	flags.Var(&conf.Constraints, "constraint", "Inline constraint of a setting, repeatable, eg : 'AppSettings_timeout=min=1 max=300'")
+}
+
+func Apply(ctx context.Context, config *CommandLineConfig) error {
+	_, err := env2js.Apply(ctx, config.LibraryOptions())
+	return err
+}
+
+func Plan(ctx context.Context, config *CommandLineConfig) error {
+	options := config.LibraryOptions()
+	options.DryRun = true
+	report, err := env2js.Apply(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	PrintChanges(Stdout, report.Changes)
+	return nil
+}
+
+func Inspect(ctx context.Context, config *CommandLineConfig) error {
+	report, err := env2js.Read(ctx, config.DefaultsOptions())
+	if err != nil {
+		return err
+	}
+
+	PrintSettingsTree(Stdout, config.Variable, report.Settings)
+	return nil
+}
+
+func Keys(ctx context.Context, config *CommandLineConfig) error {
+	report, err := env2js.Read(ctx, config.DefaultsOptions())
+	if err != nil {
+		return err
+	}
+
+	name := config.Name
+	if name == "" {
+		name = env2js.ConfigMapName(config.Variable)
+	}
+	return env2js.WrapError(env2js.ErrUsage, env2js.FormatKeys(Stdout, config.Format, env2js.MaskSettings(report.Settings), name))
+}
+
+func Docs(ctx context.Context, config *CommandLineConfig) error {
+	report, err := env2js.Read(ctx, config.DefaultsOptions())
+	if err != nil {
+		return err
+	}
+
+	return env2js.WrapError(env2js.ErrUsage, env2js.FormatDocs(Stdout, config.Format, config.Variable, env2js.MaskSettings(report.Settings)))
+}
+
+func GenerateSchema(ctx context.Context, config *CommandLineConfig) error {
+	schema, err := env2js.GenerateSchema(ctx, config.LibraryOptions())
+	if err != nil {
+		return err
+	}
+
+	return env2js.WrapError(env2js.ErrUsage, env2js.FormatSchema(Stdout, config.Format, schema))
+}
+
+func Verify(ctx context.Context, config *CommandLineConfig) error {
+	options := config.LibraryOptions()
+	report, err := env2js.Read(ctx, options)
+	if err != nil {
+		return err
+	}
+	env2js.LogUnusedOverrides(options.Logger, report.Unused)
+	if config.Verbose {
+		env2js.LogOrigins(options.Logger, report.Changes)
+	}
+
+	differences := 0
+	for _, change := range report.Changes {
+		if change.OldValue != change.NewValue {
+			LogWarning("✗ "+change.Key+" : ", env2js.MaskValue(change.OldValue, change.Secret)+" → "+env2js.MaskValue(change.NewValue, change.Secret))
+			differences++
+		}
+	}
+	if differences > 0 {
+		return env2js.WrapError(env2js.ErrValidation, fmt.Errorf("%d setting(s) of %s do not reflect the environment", differences, report.SettingsFilePath))
+	}
+
+	LogSuccess("✓ Up to date : ", report.SettingsFilePath)
+	return nil
+}
+
+func Restore(ctx context.Context, config *CommandLineConfig) error {
+	settingsFilePath, err := env2js.Restore(config.LibraryOptions())
+	if err != nil {
+		return err
+	}
+	LogSuccess("🎉 Successfuly restored : ", settingsFilePath+" 🎉")
+	return nil
+}
+
+// PrintChanges writes the changes that differ from the settings file, then a summary.
+func PrintChanges(w io.Writer, changes []env2js.Change) {
+	unchanged := 0
+	for _, change := range changes {
+		if change.OldValue == change.NewValue {
+			unchanged++
+			continue
+		}
+		fmt.Fprintf(w, "~ %s : %s → %s\n", change.Key, env2js.MaskValue(change.OldValue, change.Secret), env2js.MaskValue(change.NewValue, change.Secret))
+	}
+	fmt.Fprintf(w, "%d to change, %d unchanged\n", len(changes)-unchanged, unchanged)
+}
+
+// PrintSettingsTree writes the settings as an indented tree, one property per line.
+func PrintSettingsTree(w io.Writer, settingsVariableName string, settings []env2js.Setting) {
+	fmt.Fprintln(w, settingsVariableName)
+
+	var previousPath []string
+	for _, setting := range settings {
+		// Print the parent objects not already printed by the previous setting
+		common := 0
+		for common < len(previousPath)-1 && common < len(setting.Path)-1 && previousPath[common] == setting.Path[common] {
+			common++
+		}
+		for depth := common; depth < len(setting.Path)-1; depth++ {
+			fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth+1), setting.Path[depth])
+		}
+
+		depth := len(setting.Path) - 1
+		fmt.Fprintf(w, "%s%s: %s (%s)\n", strings.Repeat("  ", depth+1), setting.Path[depth], env2js.MaskValue(setting.Value, setting.Secret), setting.Type)
+		previousPath = setting.Path
+	}
+}
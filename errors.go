@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	// Local packages
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+// Exit codes of the program, by kind of error, see env2js.ErrUsage
+const (
+	ExitOK               = 0
+	ExitFailure          = 1
+	ExitUsage            = 2
+	ExitMissingConfig    = 3
+	ExitNoFileMatched    = 4
+	ExitVariableNotFound = 5
+	ExitParse            = 6
+	ExitValidation       = 7
+	ExitWrite            = 8
+)
+
+var exitCodes = []struct {
+	kind error
+	code int
+}{
+	{env2js.ErrUsage, ExitUsage},
+	{env2js.ErrMissingConfig, ExitMissingConfig},
+	{env2js.ErrNoFileMatched, ExitNoFileMatched},
+	{env2js.ErrVariableNotFound, ExitVariableNotFound},
+	{env2js.ErrParse, ExitParse},
+	{env2js.ErrValidation, ExitValidation},
+	{env2js.ErrWrite, ExitWrite},
+}
+
+// ExitCode returns the exit code of the kind of the error, ExitFailure for the other errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	for _, exitCode := range exitCodes {
+		if errors.Is(err, exitCode.kind) {
+			return exitCode.code
+		}
+	}
+
+	return ExitFailure
+}
+
+// ErrorMessage writes the error on a single line, its lines being separated with semicolons.
+// The parse errors keep their code frame on the following lines.
+func ErrorMessage(err error) string {
+	var parseError *env2js.ParseError
+	if errors.As(err, &parseError) {
+		return parseError.Error()
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "; ")
+}
+
+// ExitOnError logs the error on a single line, then exits with the exit code of its kind.
+func ExitOnError(err error) {
+	if err == nil {
+		return
+	}
+
+	LogError("❌ ERROR", ErrorMessage(err))
+	Exit(ExitCode(err))
+}
@@ -0,0 +1,145 @@
+package main_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	. "github.com/fleroy-isagri/env2js"
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+	"github.com/fleroy-isagri/env2js/utils"
+)
+
+var _ = Describe("Errors", func() {
+	DescribeTable("ExitCode",
+		func(err error, expected int) {
+			Expect(ExitCode(err)).To(Equal(expected))
+		},
+		Entry("no error", nil, ExitOK),
+		Entry("plain error", errors.New("boom"), ExitFailure),
+		Entry("usage", env2js.WrapError(env2js.ErrUsage, errors.New("boom")), ExitUsage),
+		Entry("missing configuration", env2js.WrapError(env2js.ErrMissingConfig, errors.New("boom")), ExitMissingConfig),
+		Entry("no file matched", env2js.WrapError(env2js.ErrNoFileMatched, errors.New("boom")), ExitNoFileMatched),
+		Entry("variable not found", env2js.WrapError(env2js.ErrVariableNotFound, errors.New("boom")), ExitVariableNotFound),
+		Entry("parse", env2js.WrapError(env2js.ErrParse, errors.New("boom")), ExitParse),
+		Entry("validation", env2js.WrapError(env2js.ErrValidation, errors.New("boom")), ExitValidation),
+		Entry("write", env2js.WrapError(env2js.ErrWrite, errors.New("boom")), ExitWrite),
+		Entry("wrapped again", fmt.Errorf("apply : %w", env2js.WrapError(env2js.ErrWrite, errors.New("boom"))), ExitWrite),
+	)
+
+	Describe("ErrorMessage", func() {
+		It("should write the error on a single line", func() {
+			// Arrange
+			err := errors.New("2 setting(s) failed validation\n  AppSettings_apiRoot : required\n\n  AppSettings_apiKey : too short\n")
+			// Act
+			message := ErrorMessage(err)
+			// Assert
+			Expect(message).To(Equal("2 setting(s) failed validation; AppSettings_apiRoot : required; AppSettings_apiKey : too short"))
+		})
+
+		It("should keep the code frame of the parse errors", func() {
+			// Arrange
+			jsBytes := []byte("const AppSettings = @;")
+			_, err := env2js.ParseJS(jsBytes)
+			// Act
+			message := ErrorMessage(env2js.WrapError(env2js.ErrParse, env2js.NewParseError("main.js", jsBytes, err)))
+			// Assert
+			Expect(message).To(Equal("main.js:1:21 : unexpected @ in expression\n> 1 | const AppSettings = @;\n    |                     ^"))
+		})
+	})
+
+	Describe("ExitOnError", func() {
+		var mockUtils *MockUtils
+		BeforeEach(func() {
+			mockUtils = new(MockUtils)
+			LogError = mockUtils.LogError
+			Exit = new(MockOs).Exit
+		})
+
+		AfterEach(func() {
+			LogError = utils.LogError
+			Exit = os.Exit
+		})
+
+		It("should log the error then exit with the code of its kind", func() {
+			// Arrange
+			err := env2js.WrapError(env2js.ErrNoFileMatched, errors.New("No file found with pattern: tests/toto*.js"))
+			// Act
+			Expect(func() { ExitOnError(err) }).To(PanicWith("Mock Exit panic with code : 4"))
+			// Assert
+			Expect(mockUtils.Errors).To(Equal([]string{"❌ ERROR : No file found with pattern: tests/toto*.js"}))
+		})
+
+		It("should not exit without error", func() {
+			Expect(func() { ExitOnError(nil) }).NotTo(Panic())
+			Expect(mockUtils.Errors).To(BeEmpty())
+		})
+	})
+
+	Describe("Run", func() {
+		var mockUtils *MockUtils
+		BeforeEach(func() {
+			mockUtils = new(MockUtils)
+			LogSuccess = mockUtils.LogSuccess
+			LogWarning = mockUtils.LogWarning
+		})
+
+		AfterEach(func() {
+			Getenv = os.Getenv
+		})
+
+		It("should return a usage error on an unknown flag", func() {
+			Expect(Run("prog", []string{"-toto"})).To(MatchError(env2js.ErrUsage))
+		})
+
+		It("should return a usage error on an unknown command instead of applying the values", func() {
+			// Act
+			err := Run("prog", []string{"-file", "tests/example.js", "-variable", "AppSettings", "verify"})
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrUsage))
+			Expect(ExitCode(err)).To(Equal(ExitUsage))
+		})
+
+		It("should return a missing configuration error without settings folder", func() {
+			// Arrange
+			Getenv = func(key string) string { return "" }
+			// Act
+			err := Run("prog", []string{"plan"})
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrMissingConfig))
+			Expect(ExitCode(err)).To(Equal(ExitMissingConfig))
+		})
+
+		It("should return a no file matched error when no settings file matches the prefix", func() {
+			// Act
+			err := Run("prog", []string{"plan", "-folder", "tests", "-prefix", "toto", "-variable", "AppSettings"})
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrNoFileMatched))
+		})
+
+		DescribeTable("should return the kind of the error of the library",
+			func(args []string, kind error, exitCode int) {
+				// Arrange
+				Getenv = func(key string) string { return "" }
+				// Act
+				err := Run("prog", append([]string{"plan", "-variable", "AppSettings"}, args...))
+				// Assert
+				Expect(err).To(MatchError(kind))
+				Expect(ExitCode(err)).To(Equal(exitCode))
+			},
+			Entry("unknown value source", []string{"-file", "tests/example.js", "-precedence", "set,toto"}, env2js.ErrUsage, ExitUsage),
+			Entry("missing settings file", []string{"-file", "tests/toto.js"}, env2js.ErrNoFileMatched, ExitNoFileMatched),
+			Entry("missing env file", []string{"-file", "tests/example.js", "-env-file", "tests/toto.env"}, env2js.ErrMissingConfig, ExitMissingConfig),
+			Entry("unset required variable", []string{"-file", "tests/example.js", "-interpolate", "-set", "AppSettings_MyKey=${TOTO:?required}"}, env2js.ErrMissingConfig, ExitMissingConfig),
+			Entry("missing age identity", []string{"-file", "tests/example.js", "-set", "AppSettings_MyKey=ENC[age,dG90bw==]"}, env2js.ErrMissingConfig, ExitMissingConfig),
+			Entry("invalid interpolation", []string{"-file", "tests/example.js", "-interpolate", "-set", "AppSettings_MyKey=${TOTO"}, env2js.ErrValidation, ExitValidation),
+			Entry("unreadable schema file", []string{"-file", "tests/example.js", "-schema", "tests/toto.json"}, env2js.ErrMissingConfig, ExitMissingConfig),
+			Entry("malformed constraint", []string{"-file", "tests/example.js", "-constraint", "AppSettings_MyKey"}, env2js.ErrUsage, ExitUsage),
+			Entry("violated constraint", []string{"-file", "tests/example.js", "-constraint", "AppSettings_MyKey=enum=a|b"}, env2js.ErrValidation, ExitValidation),
+		)
+	})
+})
@@ -2,19 +2,18 @@ package main
 
 import (
 	"bytes"
-	"errors"
+	"context"
 	"flag"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	// Local packages
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
 	"github.com/fleroy-isagri/env2js/utils"
-
-	"github.com/tdewolff/parse/v2"
-	"github.com/tdewolff/parse/v2/js"
 )
 
 // Wrap the library method for the sake of unit-tests that needs to mock the returns.
@@ -22,18 +21,23 @@ import (
 // // Testing it without mock would imply to cover the environment variable erasing.
 // // Which means that windows & linux would have two different behaviour.
 var (
-	Getenv      = os.Getenv
-	Exit        = os.Exit
-	ReadFile    = os.ReadFile
-	WriteFile   = os.WriteFile
-	HandleError = utils.HandleError
-	LogSuccess  = utils.LogSuccess
+	Getenv     = os.Getenv
+	Environ    = os.Environ
+	Exit       = os.Exit
+	LogError   = utils.LogError
+	LogSuccess = utils.LogSuccess
+	LogWarning = utils.LogWarning
+
+	// Output of the commands, the logs being written on the standard error
+	Stdout io.Writer = os.Stdout
 )
 
 const (
 	SettingsFolderPathEnvKey   string = "SETTINGS_FOLDER_PATH"
 	SettingsFilePrefixEnvKey   string = "SETTINGS_FILE_PREFIX"
 	SettingsVariableNameEnvKey string = "SETTINGS_VARIABLE_NAME"
+	SettingsLogFormatEnvKey    string = "SETTINGS_LOG_FORMAT"
+	SettingsLogLevelEnvKey     string = "SETTINGS_LOG_LEVEL"
 )
 
 var (
@@ -45,143 +49,83 @@ var (
 	BuiltBy string
 )
 
-type Walker struct {
-	CurrentPath         []string
-	SettingVariableName string
-}
-
-func (w *Walker) Enter(n js.INode) js.IVisitor {
-	switch n := n.(type) {
-	case *js.BindingElement:
-		if n.Binding.String() != w.SettingVariableName {
-			return nil
-		}
-	case *js.Property:
-		w.CurrentPath = append(w.CurrentPath, n.Name.String())
-		if valueExpression, ok := n.Value.(*js.LiteralExpr); ok {
-			if newStringValue, ok := GetEnvValue(w.CurrentPath); ok {
-				UpdateData(valueExpression, newStringValue)
-			}
-			w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
-			return nil
-		}
-		// Cas des propriété booléenne qui sont transformées en UnaryExpr par l'optimisation du build angular
-		// true => !0
-		// false => !1
-		if valueExpression, ok := n.Value.(*js.UnaryExpr); ok {
-			if valueExpression.Op == js.NotToken && valueExpression.X.(*js.LiteralExpr).TokenType == js.IntegerToken {
-				if newStringValue, ok := GetEnvValue(w.CurrentPath); ok {
-					if newStringValue == "true" {
-						valueExpression.X = &js.LiteralExpr{Data: []byte("0"), TokenType: js.IntegerToken}
-					}
-					if newStringValue == "false" {
-						valueExpression.X = &js.LiteralExpr{Data: []byte("1"), TokenType: js.IntegerToken}
-					}
-				}
-			}
-			w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
-			return nil
-		}
-	case *js.ArrayExpr:
-		for i, item := range n.List {
-			if item.Value != nil {
-				if valueExpression, ok := item.Value.(*js.LiteralExpr); ok {
-					w.CurrentPath = append(w.CurrentPath, "["+fmt.Sprint(i)+"]")
-					if newStringValue, ok := GetEnvValue(w.CurrentPath); ok {
-						UpdateData(valueExpression, newStringValue)
-					}
-					w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
-				}
-			}
-		}
-	}
-	return w
-}
-
-func (w *Walker) Exit(n js.INode) {
-	switch n.(type) {
-	case *js.Property:
-		w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
-	case *js.PropertyName:
-	}
-}
-
-func GetEnvValue(path []string) (string, bool) {
-	// TODO : use settingsVariableName instead
-	computedKey := "AppSettings_"
-	computedKey += strings.Join(path, "_")
-	if envValue := Getenv(computedKey); envValue != "" {
-		return envValue, true
-	}
-
-	return "", false
-}
-
-func GetEnvOrPanic(value string) string {
-	env := Getenv(value)
+// GetRequiredEnv returns the value of the environment variable, a missing configuration error when it is not set.
+func GetRequiredEnv(envKey string) (string, error) {
+	env := Getenv(envKey)
 	if env == "" {
-		HandleError(errors.New("No environment key for : " + value))
+		return "", env2js.WrapError(env2js.ErrMissingConfig, fmt.Errorf("missing environment variable %s", envKey))
 	}
 
-	return env
+	return env, nil
 }
 
-func UpdateData(valueExpression *js.LiteralExpr, newValue string) {
-	if valueExpression.TokenType.String() == "String" {
-		valueExpression.Data = []byte("'" + newValue + "'")
-		return
+// GetFlagOrRequiredEnv gives precedence to the command-line flag value over the environment variable.
+// One of them must be set.
+func GetFlagOrRequiredEnv(flagValue string, envKey string, flagName string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
 	}
 
-	// Cas des booléens. Il y a TrueToken et FalseToken pour les valeurs de type booléen retourné par le parser
-	if valueExpression.TokenType.String() == "true" || valueExpression.TokenType.String() == "false" {
-		valueExpression.Data = []byte(newValue)
-		return
-	}
-
-	if valueExpression.TokenType.String() == "Decimal" {
-		valueExpression.Data = []byte(newValue)
-		return
+	env, err := GetRequiredEnv(envKey)
+	if err != nil {
+		return "", env2js.WrapError(env2js.ErrMissingConfig, fmt.Errorf("missing %s, set the environment variable or -%s", envKey, flagName))
 	}
 
-	if valueExpression.TokenType.String() == "Integer" {
-		valueExpression.Data = []byte(newValue)
-		return
-	}
+	return env, nil
 }
 
-func DefineFilePath(settingsFolderPath string, settingsFilePrefix string) (string, error) {
-	settingsSearchFilePattern := filepath.Join(settingsFolderPath, settingsFilePrefix) + "*.js"
-	fileList, err := filepath.Glob(settingsSearchFilePattern)
-	if err != nil {
-		return "", err
+// GetConfigFileLocationValue reads the settings file location from the command-line flags,
+// then from the environment variables. The resolved values are stored in the config.
+func GetConfigFileLocationValue(config *CommandLineConfig) (string, string, string, error) {
+	var settingsFolderPath, settingsFilePrefix string
+	var err error
+	if config.File != "" {
+		// The settings file is given directly : no globbing, the folder is only the root of the output layout
+		settingsFolderPath = config.Folder
+		if settingsFolderPath == "" {
+			settingsFolderPath = filepath.Dir(config.File)
+		}
+		LogSuccess("✓ file: ", config.File)
+	} else {
+		if settingsFolderPath, err = GetFlagOrRequiredEnv(config.Folder, SettingsFolderPathEnvKey, "folder"); err != nil {
+			return "", "", "", err
+		}
+		if settingsFilePrefix, err = GetFlagOrRequiredEnv(config.Prefix, SettingsFilePrefixEnvKey, "prefix"); err != nil {
+			return "", "", "", err
+		}
+		LogSuccess("✓ "+SettingsFolderPathEnvKey+": ", settingsFolderPath)
+		LogSuccess("✓ "+SettingsFilePrefixEnvKey+": ", settingsFilePrefix)
 	}
 
-	if conditions := len(fileList); conditions == 0 {
-		return "", fmt.Errorf("No file found with pattern: " + settingsSearchFilePattern)
+	settingsVariableName, err := GetFlagOrRequiredEnv(config.Variable, SettingsVariableNameEnvKey, "variable")
+	if err != nil {
+		return "", "", "", err
 	}
-
-	settingsFilePath := fileList[0]
-	return settingsFilePath, nil
-}
-
-func GetConfigFileLocationValue() (string, string, string) {
-	settingsFolderPath := GetEnvOrPanic(SettingsFolderPathEnvKey)
-
-	settingsFilePrefix := GetEnvOrPanic(SettingsFilePrefixEnvKey)
-
-	settingsVariableName := GetEnvOrPanic(SettingsVariableNameEnvKey)
-
-	LogSuccess("✓ "+SettingsFolderPathEnvKey+": ", settingsFolderPath)
-	LogSuccess("✓ "+SettingsFilePrefixEnvKey+": ", settingsFilePrefix)
 	LogSuccess("✓ "+SettingsVariableNameEnvKey+": ", settingsVariableName)
 
-	return settingsFolderPath, settingsFilePrefix, settingsVariableName
+	config.Folder, config.Prefix, config.Variable = settingsFolderPath, settingsFilePrefix, settingsVariableName
+	return settingsFolderPath, settingsFilePrefix, settingsVariableName, nil
 }
 
 // https://eli.thegreenplace.net/2020/testing-flag-parsing-in-go-programs/
 type CommandLineConfig struct {
 	Version bool
 
+	// Logs, see utils.Configure
+	LogFormat string
+	LogLevel  string
+	Quiet     bool
+
+	// Subcommand to run, see Commands
+	Command string
+
+	// Options of the library, the location of the settings file taking precedence over the environment variables
+	env2js.Options
+
+	// Output of the keys command
+	Format string
+	Name   string
+
 	// args are the positional (non-flag) command-line arguments.
 	Args []string
 }
@@ -193,13 +137,35 @@ type CommandLineConfig struct {
 // A special case is usage requests with -h or -help: then the error
 // flag.ErrHelp is returned and output will contain the usage message.
 func ParseFlags(progname string, args []string) (config *CommandLineConfig, output string, err error) {
-	flags := flag.NewFlagSet(progname, flag.ContinueOnError)
+	// The first argument selects the subcommand, apply being the default one
+	command := &Commands[0]
+	name := progname
+	if len(args) > 0 && FindCommand(args[0]) != nil {
+		command = FindCommand(args[0])
+		name = progname + " " + command.Name
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
 	var buf bytes.Buffer
 	flags.SetOutput(&buf)
+	flags.Usage = func() {
+		fmt.Fprintf(&buf, "Usage of %s:\n", name)
+		flags.PrintDefaults()
+		if name == progname {
+			PrintCommands(&buf)
+		}
+	}
+
+	conf := CommandLineConfig{Command: command.Name}
 
-	var conf CommandLineConfig
 	// -version / --version
 	flags.BoolVar(&conf.Version, "version", false, "Display version and exit")
+	// -log-format / -log-level / -quiet
+	flags.StringVar(&conf.LogFormat, "log-format", "", "Format of the logs : "+strings.Join(utils.LogFormats, ", ")+" (env "+SettingsLogFormatEnvKey+", default "+utils.LogFormatText+")")
+	flags.StringVar(&conf.LogLevel, "log-level", "", "Minimum level of the logs : debug, info, warn, error (env "+SettingsLogLevelEnvKey+", default info)")
+	flags.BoolVar(&conf.Quiet, "quiet", false, "Only log the errors")
+	command.Flags(flags, &conf)
 
 	err = flags.Parse(args)
 	// When triggered by the "go test" or "ginkgo" command the args starts with "-test.-timeout=..." or "-ginkgo..."
@@ -210,6 +176,65 @@ func ParseFlags(progname string, args []string) (config *CommandLineConfig, outp
 		return nil, buf.String(), err
 	}
 	conf.Args = flags.Args()
+	// A misspelled subcommand or a subcommand after the flags would otherwise run apply
+	if len(conf.Args) > 0 && !isTestCommand {
+		return nil, buf.String(), env2js.WrapError(env2js.ErrUsage, fmt.Errorf("unknown command or argument %q", conf.Args[0]))
+	}
+	if conf.LogFormat == "" {
+		conf.LogFormat = Getenv(SettingsLogFormatEnvKey)
+	}
+	if conf.LogLevel == "" {
+		conf.LogLevel = Getenv(SettingsLogLevelEnvKey)
+	}
+	// Environment variables fallbacks, for the flags of the subcommand only
+	if flags.Lookup("template-dir") != nil && conf.TemplateDir == "" {
+		conf.TemplateDir = Getenv(env2js.SettingsTemplateDirEnvKey)
+	}
+	if conf.TemplateDir != "" {
+		conf.Template = true
+	}
+	if flags.Lookup("out-dir") != nil && conf.OutputDir == "" {
+		conf.OutputDir = Getenv(env2js.SettingsOutputPathEnvKey)
+	}
+	if flags.Lookup("require") != nil && len(conf.RequiredKeys) == 0 {
+		conf.RequiredKeys = env2js.SplitList(Getenv(env2js.SettingsRequiredKeysEnvKey))
+	}
+	if flags.Lookup("env-file") != nil && len(conf.EnvFiles) == 0 {
+		conf.EnvFiles = env2js.SplitList(Getenv(env2js.SettingsEnvFilesEnvKey))
+	}
+	if flags.Lookup("secrets-dir") != nil && len(conf.SecretsDirs) == 0 {
+		conf.SecretsDirs = env2js.SplitList(Getenv(env2js.SettingsSecretsDirsEnvKey))
+	}
+	if flags.Lookup("vault-addr") != nil && conf.VaultAddr == "" {
+		conf.VaultAddr = Getenv(env2js.VaultAddrEnvKey)
+	}
+	if flags.Lookup("vault-path") != nil && conf.VaultPath == "" {
+		conf.VaultPath = Getenv(env2js.SettingsVaultPathEnvKey)
+	}
+	if flags.Lookup("kv-addr") != nil && conf.KVAddr == "" {
+		conf.KVAddr = Getenv(env2js.SettingsKVAddrEnvKey)
+	}
+	if flags.Lookup("kv-prefix") != nil && conf.KVPrefix == "" {
+		conf.KVPrefix = Getenv(env2js.SettingsKVPrefixEnvKey)
+	}
+	if flags.Lookup("age-key-file") != nil && conf.AgeKeyFile == "" {
+		conf.AgeKeyFile = Getenv(env2js.SettingsAgeKeyFileEnvKey)
+	}
+	if flags.Lookup("interpolate") != nil && !conf.Interpolate {
+		conf.Interpolate, _ = strconv.ParseBool(Getenv(env2js.SettingsInterpolateEnvKey))
+	}
+	if flags.Lookup("transforms") != nil && conf.TransformsFile == "" {
+		conf.TransformsFile = Getenv(env2js.SettingsTransformsPathEnvKey)
+	}
+	if flags.Lookup("precedence") != nil && len(conf.Precedence) == 0 {
+		conf.Precedence = env2js.SplitList(Getenv(env2js.SettingsPrecedenceEnvKey))
+	}
+	if flags.Lookup("secret-pattern") != nil && len(conf.SecretPatterns) == 0 {
+		conf.SecretPatterns = env2js.SplitList(Getenv(env2js.SettingsSecretPatternsEnvKey))
+	}
+	if flags.Lookup("schema") != nil && conf.SchemaFile == "" {
+		conf.SchemaFile = Getenv(env2js.SettingsSchemaPathEnvKey)
+	}
 
 	if conf.Version {
 		buf.WriteString(fmt.Sprintf("version : %s\n", Version))
@@ -221,53 +246,61 @@ func ParseFlags(progname string, args []string) (config *CommandLineConfig, outp
 	return &conf, buf.String(), nil
 }
 
-func LogFlags(config *CommandLineConfig, output string, err error) {
+// LogFlags prints the usage or the version when requested, and tells whether the program is done.
+// The invalid flags are returned as usage errors.
+func LogFlags(config *CommandLineConfig, output string, err error) (bool, error) {
 	if err == flag.ErrHelp {
-		HandleError(errors.New(output))
-		Exit(2)
+		fmt.Fprint(Stdout, output)
+		return true, nil
 	} else if err != nil {
-		HandleError(errors.New(output))
-		Exit(1)
+		return true, env2js.WrapError(env2js.ErrUsage, fmt.Errorf("%w, see -help", err))
 	}
 
 	if config.Version {
-		Exit(0)
+		fmt.Fprint(Stdout, output)
+		return true, nil
 	}
+
+	return false, nil
 }
 
-func WriteInConfigFile(settingsFilePath string, settingsVariableName string) {
-	// Read the JavaScript file
-	jsBytes, err := ReadFile(settingsFilePath)
-	HandleError(err)
+// Run parses the command-line arguments, locates the settings file and runs the command.
+func Run(progname string, args []string) error {
+	config, output, err := ParseFlags(progname, args)
+	if done, err := LogFlags(config, output, err); done || err != nil {
+		return err
+	}
+	if err := utils.Configure(utils.Options{Format: config.LogFormat, Level: config.LogLevel, Quiet: config.Quiet}); err != nil {
+		return env2js.WrapError(env2js.ErrUsage, err)
+	}
 
-	// Parse the JavaScript file
-	input := parse.NewInput(bytes.NewReader(jsBytes))
-	ast, err := js.Parse(input, js.Options{})
-	HandleError(err)
+	if _, _, _, err := GetConfigFileLocationValue(config); err != nil {
+		return err
+	}
 
-	// Analyse du code javascript et réalisation des modifications si nécessaire
-	js.Walk(&Walker{SettingVariableName: settingsVariableName}, ast)
+	return FindCommand(config.Command).Run(context.Background(), config)
+}
 
-	// Write the updated JavaScript file
-	// TODO : mettre à jour le fichier uniquement si des modifications ont été faite
-	// TODO : afficher les modifications apportées
-	var buffer bytes.Buffer
-	ast.JS(&buffer)
-	err = WriteFile(settingsFilePath, buffer.Bytes(), fs.ModePerm)
-	HandleError(err)
+// LibraryOptions returns the options of the library, with the environment and the logger of the command line.
+func (c *CommandLineConfig) LibraryOptions() env2js.Options {
+	options := c.Options
+	options.Getenv = Getenv
+	options.Environ = Environ
+	options.Logger = utils.Logger
 
-	LogSuccess("🎉 Successfuly updated : ", settingsFilePath+" 🎉")
+	return options
 }
 
-func Init() {
-	config, output, err := ParseFlags(os.Args[0], os.Args[1:])
-	LogFlags(config, output, err)
+// DefaultsOptions returns the options of the library listing the settings and their defaults, without reading any value.
+func (c *CommandLineConfig) DefaultsOptions() env2js.Options {
+	options := c.LibraryOptions()
+	options.DefaultsOnly = true
 
-	settingsFolderPath, settingsFilePrefix, settingsVariableName := GetConfigFileLocationValue()
-	settingsFilePath, errorDefineFilePath := DefineFilePath(settingsFolderPath, settingsFilePrefix)
-	HandleError(errorDefineFilePath)
+	return options
+}
 
-	WriteInConfigFile(settingsFilePath, settingsVariableName)
+func Init() {
+	ExitOnError(Run(os.Args[0], os.Args[1:]))
 }
 
 // Because of the lowercase letter not being accessible in the main_test package,
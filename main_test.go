@@ -1,9 +1,10 @@
 package main_test
 
 import (
+	"bytes"
 	"errors"
 	"flag"
-	"io/fs"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -11,157 +12,24 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
-	"github.com/tdewolff/parse/v2"
-	"github.com/tdewolff/parse/v2/js"
 
 	// Local Module
 	. "github.com/fleroy-isagri/env2js"
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
 )
 
 var _ = Describe("Main", func() {
 	var mockUtils *MockUtils
 	BeforeEach(func() {
 		mockUtils = new(MockUtils)
-		HandleError = mockUtils.HandleError
 		LogSuccess = mockUtils.LogSuccess
+		LogWarning = mockUtils.LogWarning
 	})
 
 	AfterEach(func() {
 		Getenv = os.Getenv
 		Exit = os.Exit
-		ReadFile = os.ReadFile
-		WriteFile = os.WriteFile
-	})
-
-	Describe("IVisitor - When calling the walk function", func() {
-		var mockOs *MockOs
-		BeforeEach(func() {
-			mockOs = new(MockOs)
-		})
-
-		It("should erase the file key value with the new value with a string value", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("Test1")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 'MyValue1'};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: 'Test1'};"))
-		})
-
-		It("should erase the file key value with the new value with a boolean value", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("true")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: false};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: true};"))
-		})
-
-		It("should erase the file key value with the new value with a unaexpression boolean value for a newvalue as true", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("true")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: !1};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: !0};"))
-		})
-
-		It("should erase the file key value with the new value with a unaexpression boolean value for a newvalue as false", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("false")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: !0};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: !1};"))
-		})
-
-		It("should erase the file key value with the new value with an integer value", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("1")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 10};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: 1};"))
-		})
-
-		It("should erase the file key value with the new value with a decimal value", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyKey").Return("1")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 0.5};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyKey: 1};"))
-		})
-
-		It("should show the current version with an array value", func() {
-			// Arrange
-			mockOs.On("Getenv", "AppSettings_MyArray_[0]").Return("Test1")
-			mockOs.On("Getenv", "AppSettings_MyArray_[1]").Return("Test2")
-			Getenv = mockOs.Getenv
-			// Act
-			result := InterpretJSStringAsAst("const AppSettings = {MyArray: ['MyValue1', 'MyValue2']};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const AppSettings = {MyArray: ['Test1', 'Test2']};"))
-		})
-
-		It("should not do anything with an invalid binding element value", func() {
-			// Act
-			result := InterpretJSStringAsAst("const WrongBindingElement = {};")
-			// Assert
-			Expect(HasNotToPanic()).To(Equal(true))
-			Expect(result).To(Equal("const WrongBindingElement = {};"))
-		})
-	})
-
-	Describe("DefineFilePath", func() {
-		Context("When there is multiple files in the test folder", func() {
-			It("should take the fisrt one in the folder", func() {
-				want := "tests/example-1.js"
-				got, err := DefineFilePath("./tests", "example")
-				if err != nil {
-					AbortSuite("File reading error")
-				}
-				Expect(filepath.ToSlash(got)).To(Equal(want))
-			})
-
-			It("should target the file with the closest name match in the folder", func() {
-				want := "tests/example-2.js"
-				got, err := DefineFilePath("./tests", "example-2")
-				if err != nil {
-					AbortSuite("File reading error")
-				}
-				Expect(filepath.ToSlash(got)).To(Equal(want))
-			})
-
-			It("should return an error if no file was found in the folder", func() {
-				_, err := DefineFilePath("./tests", "toto")
-				if err == nil {
-					AbortSuite("File should not be find")
-				}
-				Expect(filepath.ToSlash(err.Error())).To(Equal("No file found with pattern: " + filepath.ToSlash("tests/toto*.js")))
-			})
-
-			It("should return an error if a bad pattern was passed", func() {
-				_, err := DefineFilePath("[invalid[", "toto")
-				if err == nil {
-					AbortSuite("Folder should not be find")
-				}
-				Expect(err.Error()).To(Equal(filepath.ErrBadPattern.Error()))
-			})
-		})
+		Environ = os.Environ
 	})
 
 	Describe("GetConfigFileLocationValue", func() {
@@ -174,23 +42,59 @@ var _ = Describe("Main", func() {
 		})
 
 		Context("When one of the environment variable is missing", func() {
-			It("should panic regarding settingsFolderPath", func() {
+			It("should return a missing configuration error regarding settingsFolderPath", func() {
 				mockOs.On("Getenv", SettingsFolderPathEnvKey).Unset()
 				mockOs.On("Getenv", SettingsFolderPathEnvKey).Return("").Once()
 				Getenv = mockOs.Getenv
-				Expect(func() { GetConfigFileLocationValue() }).To(Panic())
+				_, _, _, err := GetConfigFileLocationValue(&CommandLineConfig{})
+				Expect(err).To(MatchError(env2js.ErrMissingConfig))
+				Expect(err).To(MatchError("missing " + SettingsFolderPathEnvKey + ", set the environment variable or -folder"))
 			})
-			It("should panic regarding settingsFilePrefix", func() {
+			It("should return a missing configuration error regarding settingsFilePrefix", func() {
 				mockOs.On("Getenv", SettingsFilePrefixEnvKey).Unset()
 				mockOs.On("Getenv", SettingsFilePrefixEnvKey).Return("").Once()
 				Getenv = mockOs.Getenv
-				Expect(func() { GetConfigFileLocationValue() }).To(Panic())
+				_, _, _, err := GetConfigFileLocationValue(&CommandLineConfig{})
+				Expect(err).To(MatchError(env2js.ErrMissingConfig))
+				Expect(err).To(MatchError("missing " + SettingsFilePrefixEnvKey + ", set the environment variable or -prefix"))
 			})
-			It("should panic regarding settingsVariableName", func() {
+			It("should return a missing configuration error regarding settingsVariableName", func() {
 				mockOs.On("Getenv", SettingsVariableNameEnvKey).Unset()
 				mockOs.On("Getenv", SettingsVariableNameEnvKey).Return("").Once()
 				Getenv = mockOs.Getenv
-				Expect(func() { GetConfigFileLocationValue() }).To(Panic())
+				_, _, _, err := GetConfigFileLocationValue(&CommandLineConfig{})
+				Expect(err).To(MatchError(env2js.ErrMissingConfig))
+				Expect(err).To(MatchError("missing " + SettingsVariableNameEnvKey + ", set the environment variable or -variable"))
+			})
+		})
+
+		Context("When the command-line flags are set", func() {
+			It("should give precedence to the flags over the environment variables", func() {
+				// Arrange
+				Getenv = mockOs.Getenv
+				config := &CommandLineConfig{Options: env2js.Options{Folder: "./tests", Variable: "AppSettings"}}
+				// Act
+				settingsFolderPath, settingsFilePrefix, settingsVariableName, err := GetConfigFileLocationValue(config)
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+				Expect(settingsFolderPath).To(Equal("./tests"))
+				Expect(settingsFilePrefix).To(Equal(SettingsFilePrefixEnvKey))
+				Expect(settingsVariableName).To(Equal("AppSettings"))
+				Expect(config.Prefix).To(Equal(SettingsFilePrefixEnvKey))
+			})
+
+			It("should not require the folder and prefix when the file is given", func() {
+				// Arrange
+				mockOs.On("Getenv", SettingsFolderPathEnvKey).Unset()
+				mockOs.On("Getenv", SettingsFilePrefixEnvKey).Unset()
+				Getenv = mockOs.Getenv
+				config := &CommandLineConfig{Options: env2js.Options{File: "tests/example-2.js"}}
+				// Act
+				settingsFolderPath, settingsFilePrefix, _, err := GetConfigFileLocationValue(config)
+				// Assert
+				Expect(err).NotTo(HaveOccurred())
+				Expect(settingsFolderPath).To(Equal("tests"))
+				Expect(settingsFilePrefix).To(BeEmpty())
 			})
 		})
 	})
@@ -215,7 +119,48 @@ var _ = Describe("Main", func() {
 			It("should display the command list", func() {
 				// Arrange
 				Version = "1.0.0"
-				expectedOutput := "Usage of prog:\n  -version\n    \tDisplay version and exit\n"
+				expectedOutput := "Usage of prog:\n" +
+					"  -age-key-file string\n    \tFile of the age identities decrypting the ENC[age,...] values and the encrypted .env files (env SETTINGS_AGE_KEY_FILE)\n" +
+					"  -constraint value\n    \tInline constraint of a setting, repeatable, eg : 'AppSettings_timeout=min=1 max=300'\n" +
+					"  -copy-assets\n    \tCopy the untouched files of the settings folder into the output directory\n" +
+					"  -env-file value\n    \t.env file read before the environment variables, repeatable (env SETTINGS_ENV_FILES, comma separated)\n" +
+					"  -file string\n    \tPath of the configuration file, instead of searching the folder with the prefix\n" +
+					"  -folder string\n    \tFolder that includes the configuration files (env SETTINGS_FOLDER_PATH)\n" +
+					"  -interpolate\n    \tExpand the ${NAME} references of the values and of the string literals (env SETTINGS_INTERPOLATE)\n" +
+					"  -kv-addr string\n    \tAddress of the Consul-compatible key-value store, enables the kv source (env SETTINGS_KV_ADDR)\n" +
+					"  -kv-prefix string\n    \tPrefix of the keys holding the settings, eg : frontend/orders/ (env SETTINGS_KV_PREFIX)\n" +
+					"  -kv-retries int\n    \tNumber of retries of the failed requests to the key-value store (default 3)\n" +
+					"  -kv-timeout duration\n    \tTimeout of every request to the key-value store (default 5s)\n" +
+					"  -log-format string\n    \tFormat of the logs : text, json (env SETTINGS_LOG_FORMAT, default text)\n" +
+					"  -log-level string\n    \tMinimum level of the logs : debug, info, warn, error (env SETTINGS_LOG_LEVEL, default info)\n" +
+					"  -out-dir string\n    \tDirectory where the updated settings file is written instead of in place (env SETTINGS_OUTPUT_PATH)\n" +
+					"  -precedence value\n    \tComma separated value sources, highest precedence first (env SETTINGS_PRECEDENCE, default set,env,vault,kv,secrets-dir,env-file)\n" +
+					"  -prefix string\n    \tConfiguration file name prefix (env SETTINGS_FILE_PREFIX)\n" +
+					"  -quiet\n    \tOnly log the errors\n" +
+					"  -require value\n    \tEnvironment variable that must be set in strict mode, repeatable (env SETTINGS_REQUIRED_KEYS, comma separated)\n" +
+					"  -schema string\n    \tJSON Schema file the updated settings must satisfy (env SETTINGS_SCHEMA_PATH)\n" +
+					"  -secret-pattern value\n    \tGlob pattern of the environment variables whose values are masked, repeatable (env SETTINGS_SECRET_PATTERNS, comma separated, default *secret*,*token*,*key*)\n" +
+					"  -secrets-dir value\n    \tDirectory of files named after the environment variables, repeatable (env SETTINGS_SECRETS_DIRS, comma separated)\n" +
+					"  -set value\n    \tValue of an environment variable, repeatable, eg : AppSettings_API_apiRoot=https://example.com/api\n" +
+					"  -strict\n    \tFail on unused environment variables or missing required keys\n" +
+					"  -template\n    \tSave the original settings file on first run and always apply overrides to it\n" +
+					"  -template-dir string\n    \tDirectory of the original settings files (default next to the settings file, env SETTINGS_TEMPLATE_DIR)\n" +
+					"  -transforms string\n    \tJSON file of the transforms of the values by environment variable name, eg : {\"API_apiKey\": \"b64 | trim\"} (env SETTINGS_TRANSFORMS_PATH)\n" +
+					"  -variable string\n    \tSettings variable name to read inside the file (env SETTINGS_VARIABLE_NAME)\n" +
+					"  -vault-addr string\n    \tAddress of the Vault server (env VAULT_ADDR)\n" +
+					"  -vault-mount string\n    \tMount path of the Vault KV v2 secrets engine (default \"secret\")\n" +
+					"  -vault-path string\n    \tPath of the Vault secret holding the settings, enables the vault source (env SETTINGS_VAULT_PATH)\n" +
+					"  -verbose\n    \tLog the source of every applied value\n" +
+					"  -version\n    \tDisplay version and exit\n" +
+					"\nCommands:\n" +
+					"  apply    Apply the environment variables to the settings file (default)\n" +
+					"  plan     Show the changes apply would make, without writing anything\n" +
+					"  inspect  Print the settings tree found in the settings file\n" +
+					"  keys     List every overridable environment variable with its default value\n" +
+					"  docs     Generate the reference documentation of the settings from their comments\n" +
+					"  schema   Generate the JSON Schema or the TypeScript declaration of the settings object\n" +
+					"  verify   Check that the settings file already reflects the environment variables\n" +
+					"  restore  Put the original settings file back, see -template\n"
 
 				// Act
 				config, output, err := ParseFlags("prog", []string{"-help"})
@@ -229,47 +174,139 @@ var _ = Describe("Main", func() {
 		})
 	})
 
-	Describe("LogVersion", func() {
-		BeforeEach(func() {
-			mockOs := new(MockOs)
-			Exit = mockOs.Exit
+	Describe("ParseFlags subcommands", func() {
+		It("should run the apply command by default", func() {
+			config, _, err := ParseFlags("prog", []string{"-strict"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Command).To(Equal(ApplyCommand))
+			Expect(config.Strict).To(BeTrue())
 		})
 
-		It("should exit when LogVersion function is called with an error", func() {
-			Expect(func() { LogFlags(nil, "", errors.New("An error")) }).To(Panic())
+		It("should select the command given as first argument", func() {
+			config, _, err := ParseFlags("prog", []string{"inspect", "-file", "tests/example.js"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Command).To(Equal(InspectCommand))
+			Expect(config.File).To(Equal("tests/example.js"))
 		})
 
-		It("should exit when LogVersion function is called with an flag.ErrHelp", func() {
-			Expect(func() { LogFlags(nil, "", flag.ErrHelp) }).To(Panic())
+		It("should only accept the flags of the command", func() {
+			config, output, err := ParseFlags("prog", []string{"inspect", "-strict"})
+			Expect(config).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(output).To(HavePrefix("flag provided but not defined: -strict\nUsage of prog inspect:\n"))
 		})
 
-		It("should exit when LogVersion function is called with an flag.ErrHelp", func() {
-			Expect(func() { LogFlags(&CommandLineConfig{Version: true}, "output", nil) }).To(Panic())
+		DescribeTable("should reject the unknown commands and the positional arguments",
+			func(args []string, argument string) {
+				config, _, err := ParseFlags("prog", args)
+				Expect(config).To(BeNil())
+				Expect(err).To(MatchError(env2js.ErrUsage))
+				Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("unknown command or argument %q", argument))))
+			},
+			Entry("misspelled command", []string{"verfy"}, "verfy"),
+			Entry("command after the flags", []string{"-file", "main.js", "-variable", "AppSettings", "verify"}, "verify"),
+			Entry("argument of a command", []string{"plan", "main.js"}, "main.js"),
+		)
+
+		It("should display the help of the command", func() {
+			// Arrange
+			expectedOutput := "Usage of prog inspect:\n" +
+				"  -file string\n    \tPath of the configuration file, instead of searching the folder with the prefix\n" +
+				"  -folder string\n    \tFolder that includes the configuration files (env SETTINGS_FOLDER_PATH)\n" +
+				"  -log-format string\n    \tFormat of the logs : text, json (env SETTINGS_LOG_FORMAT, default text)\n" +
+				"  -log-level string\n    \tMinimum level of the logs : debug, info, warn, error (env SETTINGS_LOG_LEVEL, default info)\n" +
+				"  -prefix string\n    \tConfiguration file name prefix (env SETTINGS_FILE_PREFIX)\n" +
+				"  -quiet\n    \tOnly log the errors\n" +
+				"  -secret-pattern value\n    \tGlob pattern of the environment variables whose values are masked, repeatable (env SETTINGS_SECRET_PATTERNS, comma separated, default *secret*,*token*,*key*)\n" +
+				"  -variable string\n    \tSettings variable name to read inside the file (env SETTINGS_VARIABLE_NAME)\n" +
+				"  -version\n    \tDisplay version and exit\n"
+
+			// Act
+			_, output, err := ParseFlags("prog", []string{"inspect", "-help"})
+
+			// Assert
+			Expect(err).To(Equal(flag.ErrHelp))
+			Expect(output).To(Equal(expectedOutput))
 		})
 	})
 
-	Describe("WriteInConfigFile", func() {
-		It("should not panic when calling WriteInConfigFile", func() {
-			// Arrange
-			mockOs := new(MockOs)
-			ReadFile = mockOs.ReadFile
-			WriteFile = mockOs.WriteFile
+	Describe("LogFlags", func() {
+		var stdout *bytes.Buffer
+		BeforeEach(func() {
+			stdout = new(bytes.Buffer)
+			Stdout = stdout
+		})
 
+		AfterEach(func() {
+			Stdout = os.Stdout
+		})
+
+		It("should return a usage error when the flags are invalid", func() {
+			// Act
+			done, err := LogFlags(nil, "", errors.New("flag provided but not defined: -toto"))
 			// Assert
-			Expect(func() { WriteInConfigFile("fileName", "variableName") }).NotTo(Panic())
+			Expect(done).To(BeTrue())
+			Expect(err).To(MatchError(env2js.ErrUsage))
+			Expect(err).To(MatchError("flag provided but not defined: -toto, see -help"))
+			Expect(stdout.String()).To(BeEmpty())
+		})
+
+		It("should print the usage when the help is requested", func() {
+			// Act
+			done, err := LogFlags(nil, "usage", flag.ErrHelp)
+			// Assert
+			Expect(done).To(BeTrue())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout.String()).To(Equal("usage"))
+		})
+
+		It("should print the version when it is requested", func() {
+			// Act
+			done, err := LogFlags(&CommandLineConfig{Version: true}, "version : dev\n", nil)
+			// Assert
+			Expect(done).To(BeTrue())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stdout.String()).To(Equal("version : dev\n"))
+		})
+
+		It("should let the command run otherwise", func() {
+			// Act
+			done, err := LogFlags(&CommandLineConfig{}, "", nil)
+			// Assert
+			Expect(done).To(BeFalse())
+			Expect(err).NotTo(HaveOccurred())
 		})
 	})
 
 	Describe("Init", func() {
 		It("should not panic when calling Init", func() {
 			// Arrange
+			folder := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(folder, "example-1.js"), []byte("const MockedData = {}"), 0o644)).To(Succeed())
 			mockOs := new(MockOs)
-			ReadFile = mockOs.ReadFile
-			WriteFile = mockOs.WriteFile
-			mockOs.On("Getenv", SettingsFolderPathEnvKey).Return("./tests")
+			mockOs.On("Getenv", SettingsFolderPathEnvKey).Return(folder)
 			mockOs.On("Getenv", SettingsFilePrefixEnvKey).Return("example")
-			mockOs.On("Getenv", SettingsVariableNameEnvKey).Return("AppSettings")
+			mockOs.On("Getenv", SettingsVariableNameEnvKey).Return("MockedData")
+			mockOs.On("Getenv", SettingsLogFormatEnvKey).Return("")
+			mockOs.On("Getenv", SettingsLogLevelEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsTemplateDirEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsOutputPathEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsRequiredKeysEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsEnvFilesEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsSecretsDirsEnvKey).Return("")
+			mockOs.On("Getenv", env2js.VaultAddrEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsVaultPathEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsKVAddrEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsKVPrefixEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsAgeKeyFileEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsAgeKeyEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsInterpolateEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsTransformsPathEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsPrecedenceEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsSecretPatternsEnvKey).Return("")
+			mockOs.On("Getenv", env2js.SettingsSchemaPathEnvKey).Return("")
 			Getenv = mockOs.Getenv
+			Exit = mockOs.Exit
 
 			// Assert
 			Expect(func() { Init() }).NotTo(Panic())
@@ -279,28 +316,6 @@ var _ = Describe("Main", func() {
 
 ////////////// HELPERS //////////////
 
-func InterpretJSStringAsAst(jsString string) string {
-	// Parse the JavaScript file
-	input := parse.NewInputString(jsString)
-	ast, _ := js.Parse(input, js.Options{})
-	// Analyse du code javascript et réalisation des modifications si nécessaire
-	js.Walk(&Walker{SettingVariableName: "AppSettings"}, ast)
-	return ast.JSString()
-}
-
-func ExpectToPanic(doesPanic bool, panicMessage string) {
-	recover()
-	Expect(doesPanic).To(Equal(true))
-}
-
-func HasNotToPanic() bool {
-	if r := recover(); r == nil {
-		return true
-	} else {
-		return false
-	}
-}
-
 // MockOs is a mock implementation of the os interface.
 type MockOs struct {
 	mock.Mock
@@ -325,25 +340,27 @@ func (m *MockOs) Exit(code int) {
 	panic("Mock Exit panic with code : " + strconv.Itoa(code))
 }
 
-func (m *MockOs) ReadFile(name string) ([]byte, error) {
-	return []byte("const MockedData = {}"), nil
-}
-
-func (m *MockOs) WriteFile(name string, data []byte, perm fs.FileMode) error {
-	return nil
-}
-
 // MockOs is a mock implementation of the os interface.
 type MockUtils struct {
 	mock.Mock
+
+	// Messages logged through LogError, LogSuccess and LogWarning
+	Errors    []string
+	Successes []string
+	Warnings  []string
 }
 
-// HandleError is a mocked implementation of utils.HandleError.
-func (m *MockUtils) HandleError(err error) {
-	if err != nil {
-		panic("")
-	}
+// LogError is a mocked implementation of utils.LogError.
+func (m *MockUtils) LogError(title string, log string) {
+	m.Errors = append(m.Errors, title+" : "+log)
 }
 
 // LogSuccess is a mocked implementation of utils.LogSuccess.
-func (m *MockUtils) LogSuccess(title string, log string) {}
+func (m *MockUtils) LogSuccess(title string, log string) {
+	m.Successes = append(m.Successes, title+log)
+}
+
+// LogWarning is a mocked implementation of utils.LogWarning.
+func (m *MockUtils) LogWarning(title string, log string) {
+	m.Warnings = append(m.Warnings, title+log)
+}
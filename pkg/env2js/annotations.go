@@ -0,0 +1,108 @@
+package env2js
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// Tag introducing the annotations of a property in its comment, eg : /** @env2js required format=url */
+	AnnotationTag string = "@env2js"
+
+	// Replaces the values of the secret settings in the logs
+	MaskedValue string = "********"
+)
+
+// Annotation holds the rules written in the comment of a property.
+type Annotation struct {
+	Key string
+
+	// The environment variable must be set
+	Required bool
+	// The value must not appear in the logs
+	Secret bool
+	// Constraints of the value, see ParseConstraint
+	Constraint *Schema
+}
+
+// ParseAnnotation parses the text following the annotation tag :
+// the required and secret keywords, then the constraints, eg : "enum=a|b|c min=1 max=10 secret"
+func ParseAnnotation(text string) (*Annotation, error) {
+	annotation := &Annotation{}
+	constraintTokens := []string{}
+	for _, token := range splitConstraint(text) {
+		switch token {
+		case "required":
+			annotation.Required = true
+		case "secret":
+			annotation.Secret = true
+		default:
+			constraintTokens = append(constraintTokens, token)
+		}
+	}
+
+	var err error
+	annotation.Constraint, err = parseConstraintTokens(constraintTokens)
+	return annotation, err
+}
+
+// SettingsAnnotations parses the annotations of the property comments, by environment variable name.
+func SettingsAnnotations(comments []PropertyComment, prefix string) (map[string]*Annotation, error) {
+	annotations := map[string]*Annotation{}
+	for _, comment := range comments {
+		if comment.Annotation == "" {
+			continue
+		}
+		key := prefix + strings.Join(comment.Path, "_")
+		annotation, err := ParseAnnotation(comment.Annotation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation of %s : %w", key, err)
+		}
+		annotation.Key = key
+		annotations[key] = annotation
+	}
+
+	return annotations, nil
+}
+
+// Annotate attaches the annotations to the walker, and flags the secret settings and changes,
+// including the ones whose value looks like a credential, see LooksLikeSecret.
+func (w *Walker) Annotate(annotations map[string]*Annotation) {
+	w.Annotations = annotations
+	for i, setting := range w.Settings {
+		w.Settings[i].Secret = w.IsSecret(setting.Key) || LooksLikeSecret(setting.Value)
+	}
+	for i, change := range w.Changes {
+		w.Changes[i].Secret = w.IsSecret(change.Key) || LooksLikeSecret(change.OldValue) || LooksLikeSecret(change.NewValue)
+	}
+}
+
+// IsSecret tells whether the value of the environment variable must be masked :
+// annotated as secret, matching a secret pattern, or supplied by a secret file or a sensitive source.
+func (w *Walker) IsSecret(key string) bool {
+	annotation, ok := w.Annotations[key]
+	return ok && annotation.Secret || MatchesSecretPattern(key, w.SecretPatterns) || w.Overrides.Secret(key)
+}
+
+// RequiredKeys lists the environment variables annotated as required, sorted by name.
+func (w *Walker) RequiredKeys() []string {
+	requiredKeys := []string{}
+	for key, annotation := range w.Annotations {
+		if annotation.Required {
+			requiredKeys = append(requiredKeys, key)
+		}
+	}
+	slices.Sort(requiredKeys)
+
+	return requiredKeys
+}
+
+// MaskValue hides the value of a secret setting.
+func MaskValue(value string, secret bool) string {
+	if secret {
+		return MaskedValue
+	}
+
+	return value
+}
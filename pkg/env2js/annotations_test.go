@@ -0,0 +1,110 @@
+package env2js_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Annotations", func() {
+	const jsString = `const AppSettings = {
+  /**
+   * Base URL of the orders API
+   * @env2js required format=url
+   */
+  apiRoot: 'https://example.com/api',
+  /** @env2js secret minLength=8 */
+  apiKey: 'changeme',
+  logLevel: 'info', // not an annotation
+};`
+
+	Describe("SplitAnnotation", func() {
+		It("should separate the description from the annotation", func() {
+			description, annotation := env2js.SplitAnnotation("Base URL\n@env2js required format=url")
+			Expect(description).To(Equal("Base URL"))
+			Expect(annotation).To(Equal("required format=url"))
+			_, annotation = env2js.SplitAnnotation("Timeout @env2js min=1\n@env2js max=10")
+			Expect(annotation).To(Equal("min=1 max=10"))
+		})
+	})
+
+	Describe("ParseAnnotation", func() {
+		It("should parse the keywords and the constraints", func() {
+			// Act
+			annotation, err := env2js.ParseAnnotation("enum=a|b|c min=1 max=10 secret")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotation.Secret).To(BeTrue())
+			Expect(annotation.Required).To(BeFalse())
+			Expect(annotation.Constraint.Enum).To(Equal([]any{"a", "b", "c"}))
+			Expect(*annotation.Constraint.Maximum).To(Equal(10.0))
+		})
+
+		It("should fail on unknown constraints", func() {
+			_, err := env2js.ParseAnnotation("required optional")
+			Expect(err).To(MatchError(`unknown constraint "optional"`))
+		})
+	})
+
+	Describe("SettingsAnnotations", func() {
+		It("should index the annotations by environment variable name", func() {
+			// Act
+			annotations, err := env2js.SettingsAnnotations(env2js.PropertyComments([]byte(jsString), "AppSettings"), "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(annotations).To(HaveLen(2))
+			Expect(annotations["AppSettings_apiRoot"].Required).To(BeTrue())
+			Expect(annotations["AppSettings_apiRoot"].Constraint.Format).To(Equal("url"))
+			Expect(annotations["AppSettings_apiKey"].Secret).To(BeTrue())
+		})
+
+		It("should name the property of an invalid annotation", func() {
+			_, err := env2js.SettingsAnnotations([]env2js.PropertyComment{{Path: []string{"API", "timeout"}, Annotation: "min=one"}}, "AppSettings_")
+			Expect(err).To(MatchError(`invalid annotation of AppSettings_API_timeout : constraint min expects a number, got "one"`))
+		})
+	})
+
+	Describe("Apply", func() {
+		var settingsFilePath string
+		BeforeEach(func() {
+			settingsFilePath = filepath.Join(GinkgoT().TempDir(), "main.js")
+			Expect(os.WriteFile(settingsFilePath, []byte(jsString), 0o644)).To(Succeed())
+		})
+
+		It("should mark the annotated secrets", func() {
+			// Arrange
+			options := env2js.Options{File: settingsFilePath, Variable: "AppSettings", DryRun: true, Environ: func() []string {
+				return []string{"AppSettings_apiRoot=https://example.org/api", "AppSettings_apiKey=s3cr3t-value"}
+			}}
+			// Act
+			report, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Changes).To(Equal([]env2js.Change{
+				{Key: "AppSettings_apiRoot", Path: []string{"apiRoot"}, OldValue: "https://example.com/api", NewValue: "https://example.org/api", Origin: "environment"},
+				{Key: "AppSettings_apiKey", Path: []string{"apiKey"}, OldValue: "changeme", NewValue: "s3cr3t-value", Secret: true, Origin: "environment"},
+			}))
+		})
+
+		It("should fail on the annotated rules", func() {
+			// Arrange
+			options := env2js.Options{File: settingsFilePath, Variable: "AppSettings", DryRun: true, Environ: func() []string {
+				return []string{"AppSettings_apiKey=short"}
+			}}
+			// Act
+			_, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrValidation))
+			Expect(err).To(MatchError(
+				"2 setting(s) failed validation\n" +
+					"  AppSettings_apiRoot : required environment variable is not set\n" +
+					"  AppSettings_apiKey : must be at least 8 characters long"))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package env2js
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+)
+
+const (
+	// Lines printed before and after the line of the error
+	CodeFrameContextLines int = 2
+	// Characters printed around the column of the error on the long lines, eg : minified bundles
+	CodeFrameWidth int = 80
+)
+
+// ParseError locates the syntax error of a settings file, with an excerpt of the file pointing to it.
+type ParseError struct {
+	FilePath string
+	Line     int
+	Column   int
+	Message  string
+	Frame    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d : %s\n%s", e.FilePath, e.Line, e.Column, e.Message, e.Frame)
+}
+
+// NewParseError adds the file and a code frame to the error of the JavaScript parser.
+// The other errors are only prefixed with the file.
+func NewParseError(settingsFilePath string, jsBytes []byte, err error) error {
+	var syntaxError *parse.Error
+	if !errors.As(err, &syntaxError) {
+		return fmt.Errorf("%s : %w", settingsFilePath, err)
+	}
+
+	return &ParseError{
+		FilePath: settingsFilePath,
+		Line:     syntaxError.Line,
+		Column:   syntaxError.Column,
+		Message:  syntaxError.Message,
+		Frame:    CodeFrame(jsBytes, syntaxError.Line, syntaxError.Column),
+	}
+}
+
+// CodeFrame returns the lines around the position, numbered, with a caret under the column :
+//
+//	  2 |   b: 1,
+//	> 3 |   c: @
+//	    |      ^
+//	  4 | };
+//
+// The long lines are cut to a window around the column. The line and the column start at 1, the column counting runes.
+func CodeFrame(jsBytes []byte, line int, column int) string {
+	lines := strings.Split(string(jsBytes), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first, last := max(line-CodeFrameContextLines, 1), min(line+CodeFrameContextLines, len(lines))
+	numberWidth := len(fmt.Sprint(last))
+	start := codeFrameWindowStart([]rune(strings.TrimSuffix(lines[line-1], "\r")), column)
+
+	frame := strings.Builder{}
+	for number := first; number <= last; number++ {
+		// The empty line following the final newline
+		if number == len(lines) && number != line && lines[number-1] == "" {
+			break
+		}
+		excerpt, caretOffset := codeFrameExcerpt([]rune(strings.TrimSuffix(lines[number-1], "\r")), start, column)
+		marker := " "
+		if number == line {
+			marker = ">"
+		}
+		fmt.Fprintln(&frame, strings.TrimRight(fmt.Sprintf("%s %*d | %s", marker, numberWidth, number, excerpt), " "))
+		if number == line {
+			fmt.Fprintf(&frame, "  %*s | %s^\n", numberWidth, "", caretOffset)
+		}
+	}
+
+	return strings.TrimSuffix(frame.String(), "\n")
+}
+
+// codeFrameWindowStart returns the index of the first rune printed, centering the column on the long lines.
+func codeFrameWindowStart(runes []rune, column int) int {
+	if len(runes) <= CodeFrameWidth {
+		return 0
+	}
+
+	return max(min(column-1-CodeFrameWidth/2, len(runes)-CodeFrameWidth), 0)
+}
+
+// codeFrameExcerpt returns the window of the line starting at start, with ellipses where it is cut,
+// and the white spaces leading to the column, tabs kept for the caret to stay aligned.
+func codeFrameExcerpt(runes []rune, start int, column int) (string, string) {
+	end := min(start+CodeFrameWidth, len(runes))
+	if start >= end {
+		return "", ""
+	}
+
+	excerpt := string(runes[start:end])
+	caretOffset := strings.Builder{}
+	if start > 0 {
+		excerpt = "…" + excerpt
+		caretOffset.WriteString(" ")
+	}
+	if end < len(runes) {
+		excerpt += "…"
+	}
+	for index := start; index < column-1; index++ {
+		if index < len(runes) && runes[index] == '\t' {
+			caretOffset.WriteRune('\t')
+		} else {
+			caretOffset.WriteRune(' ')
+		}
+	}
+
+	return excerpt, caretOffset.String()
+}
@@ -0,0 +1,86 @@
+package env2js_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("CodeFrame", func() {
+	jsString := "const AppSettings = {\n  a: 1,\n  b: 2,\n  c: @,\n  d: 4,\n  e: 5,\n  f: 6\n};\n"
+
+	It("should point to the column with the surrounding lines", func() {
+		// Act
+		frame := env2js.CodeFrame([]byte(jsString), 4, 6)
+		// Assert
+		Expect(frame).To(Equal("" +
+			"  2 |   a: 1,\n" +
+			"  3 |   b: 2,\n" +
+			"> 4 |   c: @,\n" +
+			"    |      ^\n" +
+			"  5 |   d: 4,\n" +
+			"  6 |   e: 5,"))
+	})
+
+	It("should stop at the first and last lines of the file", func() {
+		// Act
+		frame := env2js.CodeFrame([]byte("const AppSettings = @;\n"), 1, 21)
+		// Assert
+		Expect(frame).To(Equal("" +
+			"> 1 | const AppSettings = @;\n" +
+			"    |                     ^"))
+	})
+
+	It("should keep the tabs for the caret to stay aligned", func() {
+		// Act
+		frame := env2js.CodeFrame([]byte("var x = {\n\ta: @\n}"), 2, 5)
+		// Assert
+		Expect(frame).To(ContainSubstring("> 2 | \ta: @\n    | \t   ^"))
+	})
+
+	It("should cut the long lines around the column", func() {
+		// Arrange
+		minified := "var x={" + strings.Repeat("k:1,", 50) + "bad:@," + strings.Repeat("z:1,", 50) + "};"
+		column := strings.Index(minified, "@") + 1
+		// Act
+		frame := env2js.CodeFrame([]byte(minified), 1, column)
+		// Assert
+		lines := strings.Split(frame, "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(HavePrefix("> 1 | …"))
+		Expect(lines[0]).To(HaveSuffix("…"))
+		Expect([]rune(lines[0])).To(HaveLen(len("> 1 | ") + env2js.CodeFrameWidth + 2))
+		caret := strings.Index(lines[1], "^")
+		Expect(string([]rune(lines[0])[caret])).To(Equal("@"))
+	})
+
+	It("should return nothing for a line out of the file", func() {
+		Expect(env2js.CodeFrame([]byte(jsString), 42, 1)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("NewParseError", func() {
+	It("should locate the syntax error of the settings file", func() {
+		// Arrange
+		jsBytes := []byte("const AppSettings = {\n  c: @\n};")
+		_, err := env2js.ParseJS(jsBytes)
+		// Act
+		parseError := env2js.NewParseError("main.js", jsBytes, err)
+		// Assert
+		Expect(parseError).To(MatchError("" +
+			"main.js:2:6 : unexpected @ in expression\n" +
+			"  1 | const AppSettings = {\n" +
+			"> 2 |   c: @\n" +
+			"    |      ^\n" +
+			"  3 | };"))
+	})
+
+	It("should prefix the other errors with the file", func() {
+		Expect(env2js.NewParseError("main.js", nil, errors.New("boom"))).To(MatchError("main.js : boom"))
+	})
+})
@@ -0,0 +1,201 @@
+package env2js
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// PropertyComment is the comment written right above a property of the settings object.
+// The annotation is the text following the annotation tag, see ParseAnnotation.
+type PropertyComment struct {
+	Path       []string
+	Text       string
+	Annotation string
+}
+
+// commentScanner walks the tokens of the settings object literal.
+// The parser drops the comments, so the file is scanned with the lexer instead.
+type commentScanner struct {
+	lexer    *js.Lexer
+	pending  [][]byte
+	comments []PropertyComment
+}
+
+// PropertyComments returns the leading comments of the settings object properties, in order of appearance.
+// The paths are computed the same way as the Walker, eg : [API apiRoot] or [MyArray [0]].
+func PropertyComments(jsBytes []byte, settingsVariableName string) []PropertyComment {
+	s := &commentScanner{lexer: js.NewLexer(parse.NewInputBytes(jsBytes)), comments: []PropertyComment{}}
+
+	// Find the object literal assigned to the settings variable
+	for {
+		tt, data := s.next()
+		if tt == js.ErrorToken {
+			return s.comments
+		}
+		if tt != js.IdentifierToken || string(data) != settingsVariableName {
+			continue
+		}
+		if tt, _ = s.next(); tt != js.EqToken {
+			continue
+		}
+		if tt, _ = s.next(); tt == js.OpenBraceToken {
+			s.scanObject([]string{})
+			return s.comments
+		}
+	}
+}
+
+// CommentText strips the comment delimiters and the leading stars, and joins the lines.
+func CommentText(comments [][]byte) string {
+	lines := []string{}
+	for _, comment := range comments {
+		text := string(comment)
+		if strings.HasPrefix(text, "//") {
+			text = strings.TrimPrefix(text, "//")
+		} else {
+			text = strings.TrimSuffix(strings.TrimLeft(strings.TrimPrefix(text, "/*"), "*!"), "*/")
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*")); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// next returns the next significant token, keeping the comments found before it.
+func (s *commentScanner) next() (js.TokenType, []byte) {
+	s.pending = nil
+	for {
+		tt, data := s.lexer.Next()
+		switch tt {
+		case js.WhitespaceToken, js.LineTerminatorToken:
+		case js.CommentToken, js.CommentLineTerminatorToken:
+			s.pending = append(s.pending, slices.Clone(data))
+		default:
+			return tt, data
+		}
+	}
+}
+
+func (s *commentScanner) record(path []string, comments [][]byte) {
+	text, annotation := SplitAnnotation(CommentText(comments))
+	if text != "" || annotation != "" {
+		s.comments = append(s.comments, PropertyComment{Path: path, Text: text, Annotation: annotation})
+	}
+}
+
+// SplitAnnotation separates the description from the annotations of a comment text.
+// An annotation runs from the annotation tag to the end of its line.
+func SplitAnnotation(text string) (string, string) {
+	lines, annotations := []string{}, []string{}
+	for _, line := range strings.Split(text, "\n") {
+		description, annotation, ok := strings.Cut(line, AnnotationTag)
+		if description = strings.TrimSpace(description); description != "" {
+			lines = append(lines, description)
+		}
+		if annotation = strings.TrimSpace(annotation); ok && annotation != "" {
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	return strings.Join(lines, "\n"), strings.Join(annotations, " ")
+}
+
+// scanObject scans the properties until the closing brace.
+func (s *commentScanner) scanObject(path []string) {
+	for {
+		tt, data := s.next()
+		switch {
+		case tt == js.ErrorToken || tt == js.CloseBraceToken:
+			return
+		case tt == js.CommaToken:
+			continue
+		case js.IsIdentifierName(tt) || tt == js.StringToken || js.IsNumeric(tt):
+			key, comments := string(data), s.pending
+			if tt, _ = s.next(); tt != js.ColonToken {
+				// Shorthand property or method
+				if s.skip(tt) == js.CommaToken {
+					continue
+				}
+				return
+			}
+
+			propertyPath := append(slices.Clone(path), key)
+			s.record(propertyPath, comments)
+			tt, _ = s.next()
+			if s.scanValue(propertyPath, tt) != js.CommaToken {
+				return
+			}
+		default:
+			// Spread element or computed property name
+			if s.skip(tt) != js.CommaToken {
+				return
+			}
+		}
+	}
+}
+
+// scanArray scans the items until the closing bracket.
+func (s *commentScanner) scanArray(path []string) {
+	for index := 0; ; index++ {
+		tt, _ := s.next()
+		switch tt {
+		case js.ErrorToken, js.CloseBracketToken:
+			return
+		case js.CommaToken:
+			// Elision
+			continue
+		}
+
+		itemPath := append(slices.Clone(path), "["+strconv.Itoa(index)+"]")
+		s.record(itemPath, s.pending)
+		if s.scanValue(itemPath, tt) != js.CommaToken {
+			return
+		}
+	}
+}
+
+// scanValue scans a property value or an array item starting with the given token.
+// Returns the token ending it : a comma, or the closing brace or bracket of the parent.
+func (s *commentScanner) scanValue(path []string, tt js.TokenType) js.TokenType {
+	switch tt {
+	case js.OpenBraceToken:
+		s.scanObject(path)
+		tt, _ = s.next()
+	case js.OpenBracketToken:
+		s.scanArray(path)
+		tt, _ = s.next()
+	}
+
+	return s.skip(tt)
+}
+
+// skip consumes the tokens until the comma, closing brace or closing bracket ending the current value.
+func (s *commentScanner) skip(tt js.TokenType) js.TokenType {
+	depth := 0
+	for {
+		switch tt {
+		case js.ErrorToken:
+			return tt
+		case js.OpenBraceToken, js.OpenBracketToken, js.OpenParenToken:
+			depth++
+		case js.CloseBraceToken, js.CloseBracketToken, js.CloseParenToken:
+			if depth == 0 {
+				return tt
+			}
+			depth--
+		case js.CommaToken:
+			if depth == 0 {
+				return tt
+			}
+		}
+		tt, _ = s.next()
+	}
+}
@@ -0,0 +1,64 @@
+package env2js_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Comments", func() {
+	Describe("PropertyComments", func() {
+		It("should pair the leading comments with the property paths", func() {
+			// Arrange
+			jsString := `const Other = {
+  /** Not a setting */
+  apiRoot: 'toto',
+};
+const AppSettings = {
+  /** Whether the app is served */
+  isServed: !0,
+  API: {
+    /**
+     * Base URL of the orders API
+     * without trailing slash
+     */
+    apiRoot: 'url/server/app',
+    headers: call({ a: 1 }, [2]),
+    // Minimal supported version
+    apiVersion: 2,
+  },
+  MyArray: [
+    // First value
+    'MyValue1',
+    'MyValue2',
+  ],
+  ...spread,
+  /** After the spread */
+  MyKey: 'TATA',
+};`
+			// Act
+			comments := env2js.PropertyComments([]byte(jsString), "AppSettings")
+			// Assert
+			Expect(comments).To(Equal([]env2js.PropertyComment{
+				{Path: []string{"isServed"}, Text: "Whether the app is served"},
+				{Path: []string{"API", "apiRoot"}, Text: "Base URL of the orders API\nwithout trailing slash"},
+				{Path: []string{"API", "apiVersion"}, Text: "Minimal supported version"},
+				{Path: []string{"MyArray", "[0]"}, Text: "First value"},
+				{Path: []string{"MyKey"}, Text: "After the spread"},
+			}))
+		})
+
+		It("should return nothing when the settings variable is not found", func() {
+			Expect(env2js.PropertyComments([]byte("const Other = {/** Comment */ a: 1};"), "AppSettings")).To(BeEmpty())
+		})
+	})
+
+	Describe("CommentText", func() {
+		It("should strip the comment delimiters", func() {
+			Expect(env2js.CommentText([][]byte{[]byte("/** Single line */")})).To(Equal("Single line"))
+			Expect(env2js.CommentText([][]byte{[]byte("// First line"), []byte("// Second line")})).To(Equal("First line\nSecond line"))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package env2js
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// Maximum number of declarations listed when the settings variable is not found
+const MaxListedDeclarations int = 20
+
+// DeclarationCollector is a js.IVisitor gathering the names of the variables declared in a file.
+type DeclarationCollector struct {
+	Names []string
+}
+
+func (c *DeclarationCollector) Enter(n js.INode) js.IVisitor {
+	if n, ok := n.(*js.BindingElement); ok {
+		if binding, ok := n.Binding.(*js.Var); ok && !slices.Contains(c.Names, string(binding.Name())) {
+			c.Names = append(c.Names, string(binding.Name()))
+		}
+	}
+	return c
+}
+
+func (c *DeclarationCollector) Exit(n js.INode) {}
+
+// Declarations returns the names of the variables declared in the parsed file, in order of appearance.
+func Declarations(ast *js.AST) []string {
+	collector := &DeclarationCollector{Names: []string{}}
+	js.Walk(collector, ast)
+	return collector.Names
+}
+
+// CandidateFilePaths returns every file matching the settings file pattern, sorted by name.
+// A missing folder matches no file.
+func CandidateFilePaths(fsys FileSystem, settingsFolderPath string, settingsFilePrefix string) ([]string, error) {
+	pattern := filepath.Join(settingsFolderPath, settingsFilePrefix) + "*.js"
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(pattern)
+	entries, err := fsys.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	filePaths := []string{}
+	for _, entry := range entries {
+		filePath := filepath.Join(dir, entry.Name())
+		if matched, _ := filepath.Match(pattern, filePath); matched {
+			filePaths = append(filePaths, filePath)
+		}
+	}
+
+	return filePaths, nil
+}
+
+// FilesDeclaring returns the files declaring the variable. Unreadable or invalid files are skipped.
+func FilesDeclaring(fsys FileSystem, filePaths []string, variableName string) []string {
+	declaringFilePaths := []string{}
+	for _, filePath := range filePaths {
+		jsBytes, err := fsys.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		ast, err := ParseJS(jsBytes)
+		if err != nil {
+			continue
+		}
+		if slices.Contains(Declarations(ast), variableName) {
+			declaringFilePaths = append(declaringFilePaths, filePath)
+		}
+	}
+
+	return declaringFilePaths
+}
+
+// VariableNotFoundError describes why the settings variable is missing from the settings file :
+// the variables the file does declare, and the other candidate files declaring the settings variable.
+func VariableNotFoundError(fsys FileSystem, settingsFilePath string, settingsVariableName string, ast *js.AST, candidateFilePaths []string) error {
+	message := "settings variable " + settingsVariableName + " not found in " + settingsFilePath
+
+	declarations := Declarations(ast)
+	switch {
+	case len(declarations) == 0:
+		message += "\n  no variable declared in this file"
+	case len(declarations) > MaxListedDeclarations:
+		message += "\n  declared variables : " + strings.Join(declarations[:MaxListedDeclarations], ", ") + fmt.Sprintf(" and %d more", len(declarations)-MaxListedDeclarations)
+	default:
+		message += "\n  declared variables : " + strings.Join(declarations, ", ")
+	}
+
+	otherFilePaths := []string{}
+	for _, candidateFilePath := range candidateFilePaths {
+		if filepath.Clean(candidateFilePath) != filepath.Clean(settingsFilePath) {
+			otherFilePaths = append(otherFilePaths, candidateFilePath)
+		}
+	}
+	if declaringFilePaths := FilesDeclaring(fsys, otherFilePaths, settingsVariableName); len(declaringFilePaths) > 0 {
+		message += "\n  other files declaring " + settingsVariableName + " : " + strings.Join(declaringFilePaths, ", ")
+	} else if len(otherFilePaths) > 0 {
+		message += "\n  none of the other matching files declares " + settingsVariableName
+	}
+
+	return fmt.Errorf("%s", message)
+}
@@ -0,0 +1,59 @@
+package env2js_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Declarations", func() {
+	parseJSString := func(jsString string) *js.AST {
+		ast, _ := js.Parse(parse.NewInputString(jsString), js.Options{})
+		return ast
+	}
+
+	Describe("Declarations", func() {
+		It("should list the declared variables in order of appearance", func() {
+			ast := parseJSString("const Test = {}; let a = 1, b = 2; function f() { var c = 3; }")
+			Expect(env2js.Declarations(ast)).To(Equal([]string{"Test", "a", "b", "c"}))
+		})
+	})
+
+	Describe("FilesDeclaring", func() {
+		It("should find the files declaring the variable", func() {
+			Expect(env2js.FilesDeclaring(env2js.OSFileSystem{}, []string{"../../tests/example.js", "../../tests/missing.js"}, "AppSettings2")).To(Equal([]string{"../../tests/example.js"}))
+		})
+	})
+
+	Describe("VariableNotFoundError", func() {
+		It("should report the declarations of the file and the other files declaring the variable", func() {
+			// Arrange
+			folder := GinkgoT().TempDir()
+			chunkFilePath := filepath.Join(folder, "main.1.js")
+			settingsFilePath := filepath.Join(folder, "main.2.js")
+			Expect(os.WriteFile(chunkFilePath, []byte("const Vendor = {};"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {};"), 0o644)).To(Succeed())
+			candidateFilePaths, _ := env2js.CandidateFilePaths(env2js.OSFileSystem{}, folder, "main")
+			// Act
+			err := env2js.VariableNotFoundError(env2js.OSFileSystem{}, chunkFilePath, "AppSettings", parseJSString("const Vendor = {};"), candidateFilePaths)
+			// Assert
+			Expect(err).To(MatchError("settings variable AppSettings not found in " + chunkFilePath +
+				"\n  declared variables : Vendor" +
+				"\n  other files declaring AppSettings : " + settingsFilePath))
+		})
+
+		It("should report when no other file declares the variable", func() {
+			err := env2js.VariableNotFoundError(env2js.OSFileSystem{}, "../../tests/example-1.js", "Unknown", parseJSString(""), []string{"../../tests/example-1.js", "../../tests/example-2.js"})
+			Expect(err).To(MatchError("settings variable Unknown not found in ../../tests/example-1.js" +
+				"\n  no variable declared in this file" +
+				"\n  none of the other matching files declares Unknown"))
+		})
+	})
+})
@@ -0,0 +1,132 @@
+package env2js
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+const (
+	SettingsAgeKeyEnvKey     string = "SETTINGS_AGE_KEY"
+	SettingsAgeKeyFileEnvKey string = "SETTINGS_AGE_KEY_FILE"
+
+	// Encrypted values are written ENC[age,<base64 of the age ciphertext>]
+	EncryptedValuePrefix string = "ENC[age,"
+	EncryptedValueSuffix string = "]"
+
+	// Header of the binary age files
+	ageFileHeader string = "age-encryption.org/v1"
+)
+
+// Decrypter decrypts the encrypted values and files with the age identities
+// of the SETTINGS_AGE_KEY environment variable, or of the -age-key-file file.
+// The identities are only read when the first encrypted value is met.
+type Decrypter struct {
+	Key        string
+	KeyFile    string
+	FileSystem FileSystem
+
+	identities []age.Identity
+	err        error
+}
+
+// NewDecrypter reads the age identities from the environment, or their location from the options.
+func NewDecrypter(options Options) *Decrypter {
+	options = options.withDefaults()
+	return &Decrypter{Key: options.Getenv(SettingsAgeKeyEnvKey), KeyFile: options.AgeKeyFile, FileSystem: options.FileSystem}
+}
+
+// IsEncryptedValue tells whether the value is written ENC[age,...]
+func IsEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, EncryptedValuePrefix) && strings.HasSuffix(value, EncryptedValueSuffix)
+}
+
+// IsEncryptedFile tells whether the content is an age file, armored or not.
+func IsEncryptedFile(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(ageFileHeader)) || bytes.HasPrefix(bytes.TrimSpace(content), []byte(armor.Header))
+}
+
+// EncryptValue encrypts the value for the recipients, as ENC[age,...]
+func EncryptValue(value string, recipients ...age.Recipient) (string, error) {
+	ciphertext := bytes.Buffer{}
+	writer, err := age.Encrypt(&ciphertext, recipients...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(writer, value); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return EncryptedValuePrefix + base64.StdEncoding.EncodeToString(ciphertext.Bytes()) + EncryptedValueSuffix, nil
+}
+
+// DecryptValue decrypts a value written ENC[age,...]
+func (d *Decrypter) DecryptValue(value string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, EncryptedValuePrefix), EncryptedValueSuffix))
+	if err != nil {
+		return "", errors.New("invalid base64 ciphertext")
+	}
+
+	plaintext, err := d.decrypt(bytes.NewReader(ciphertext))
+	return string(plaintext), err
+}
+
+// DecryptFile decrypts the content of an age file, armored or not.
+func (d *Decrypter) DecryptFile(content []byte) ([]byte, error) {
+	var reader io.Reader = bytes.NewReader(content)
+	if !bytes.HasPrefix(content, []byte(ageFileHeader)) {
+		reader = armor.NewReader(bytes.NewReader(bytes.TrimSpace(content)))
+	}
+
+	return d.decrypt(reader)
+}
+
+func (d *Decrypter) decrypt(ciphertext io.Reader) ([]byte, error) {
+	identities, err := d.Identities()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := age.Decrypt(ciphertext, identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
+
+// Identities reads the age identities once.
+func (d *Decrypter) Identities() ([]age.Identity, error) {
+	if d == nil {
+		return nil, WrapError(ErrMissingConfig, fmt.Errorf("no age identity, set %s or -age-key-file", SettingsAgeKeyEnvKey))
+	}
+	if d.identities != nil || d.err != nil {
+		return d.identities, d.err
+	}
+
+	var keys []byte
+	if d.Key != "" {
+		keys = []byte(d.Key)
+	} else if d.KeyFile != "" {
+		keys, d.err = fileSystemOrOS(d.FileSystem).ReadFile(d.KeyFile)
+	} else {
+		d.err = fmt.Errorf("no age identity, set %s or -age-key-file", SettingsAgeKeyEnvKey)
+	}
+	if d.err == nil {
+		if d.identities, d.err = age.ParseIdentities(bytes.NewReader(keys)); d.err != nil {
+			d.err = fmt.Errorf("invalid age identities : %w", d.err)
+		}
+	}
+	d.err = WrapError(ErrMissingConfig, d.err)
+
+	return d.identities, d.err
+}
@@ -0,0 +1,112 @@
+package env2js_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Decrypt", func() {
+	var identity *age.X25519Identity
+	var keyFilePath string
+	BeforeEach(func() {
+		var err error
+		identity, err = age.GenerateX25519Identity()
+		Expect(err).NotTo(HaveOccurred())
+		keyFilePath = filepath.Join(GinkgoT().TempDir(), "keys.txt")
+		Expect(os.WriteFile(keyFilePath, []byte("# created for the tests\n"+identity.String()+"\n"), 0o600)).To(Succeed())
+	})
+
+	Describe("DecryptValue", func() {
+		It("should decrypt the values encrypted for the identity of the key file", func() {
+			// Arrange
+			encrypted, err := env2js.EncryptValue("s3cr3t", identity.Recipient())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env2js.IsEncryptedValue(encrypted)).To(BeTrue())
+			// Act
+			value, err := (&env2js.Decrypter{KeyFile: keyFilePath}).DecryptValue(encrypted)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("s3cr3t"))
+		})
+
+		It("should read the identity from the environment first", func() {
+			// Arrange
+			getenv := func(key string) string { return map[string]string{env2js.SettingsAgeKeyEnvKey: identity.String()}[key] }
+			encrypted, _ := env2js.EncryptValue("s3cr3t", identity.Recipient())
+			// Act
+			value, err := env2js.NewDecrypter(env2js.Options{Getenv: getenv, AgeKeyFile: "missing.txt"}).DecryptValue(encrypted)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("s3cr3t"))
+		})
+
+		It("should fail without identity", func() {
+			_, err := (&env2js.Decrypter{}).DecryptValue("ENC[age,YQ==]")
+			Expect(err).To(MatchError("no age identity, set SETTINGS_AGE_KEY or -age-key-file"))
+		})
+	})
+
+	Describe("Overrides", func() {
+		It("should decrypt the encrypted values and mask them", func() {
+			// Arrange
+			encrypted, _ := env2js.EncryptValue("s3cr3t", identity.Recipient())
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_apiKey=" + encrypted, "AppSettings_title=Orders"})
+			overrides.Decrypter = &env2js.Decrypter{KeyFile: keyFilePath}
+			// Act
+			apiKey, ok := overrides.Lookup([]string{"apiKey"})
+			title, _ := overrides.Lookup([]string{"title"})
+			// Assert
+			Expect(ok).To(BeTrue())
+			Expect(apiKey).To(Equal("s3cr3t"))
+			Expect(overrides.Secret("AppSettings_apiKey")).To(BeTrue())
+			Expect(title).To(Equal("Orders"))
+			Expect(overrides.Secret("AppSettings_title")).To(BeFalse())
+		})
+
+		It("should name the key of the values that cannot be decrypted", func() {
+			// Arrange
+			other, _ := age.GenerateX25519Identity()
+			encrypted, _ := env2js.EncryptValue("s3cr3t", other.Recipient())
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_apiKey=" + encrypted})
+			overrides.Decrypter = &env2js.Decrypter{KeyFile: keyFilePath}
+			// Act
+			_, ok := overrides.Lookup([]string{"apiKey"})
+			// Assert
+			Expect(ok).To(BeFalse())
+			Expect(overrides.Err()).To(MatchError(HavePrefix("cannot decrypt AppSettings_apiKey : no identity matched any of the recipients")))
+			Expect(overrides.Err().Error()).NotTo(ContainSubstring("s3cr3t"))
+		})
+	})
+
+	Describe("NewDotenvSource", func() {
+		It("should decrypt the armored env files", func() {
+			// Arrange
+			ciphertext := bytes.Buffer{}
+			armorWriter := armor.NewWriter(&ciphertext)
+			writer, err := age.Encrypt(armorWriter, identity.Recipient())
+			Expect(err).NotTo(HaveOccurred())
+			_, _ = io.WriteString(writer, "AppSettings_apiKey=s3cr3t\n")
+			Expect(writer.Close()).To(Succeed())
+			Expect(armorWriter.Close()).To(Succeed())
+			envFilePath := filepath.Join(GinkgoT().TempDir(), ".env.age")
+			Expect(os.WriteFile(envFilePath, ciphertext.Bytes(), 0o644)).To(Succeed())
+			// Act
+			source, err := env2js.NewDotenvSource(env2js.OSFileSystem{}, envFilePath, &env2js.Decrypter{KeyFile: keyFilePath})
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.Sensitive()).To(BeTrue())
+			value, _ := source.Lookup("AppSettings_apiKey")
+			Expect(value).To(Equal("s3cr3t"))
+		})
+	})
+})
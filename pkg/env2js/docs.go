@@ -0,0 +1,69 @@
+package env2js
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	DocsFormatMarkdown string = "markdown"
+	DocsFormatHTML     string = "html"
+)
+
+var DocsFormats = []string{DocsFormatMarkdown, DocsFormatHTML}
+
+var docsHTMLTemplate = template.Must(template.New("docs").Parse(`<h1>{{.Title}}</h1>
+<table>
+  <thead>
+    <tr><th>Environment variable</th><th>Type</th><th>Default</th><th>Description</th></tr>
+  </thead>
+  <tbody>
+{{- range .Settings}}
+    <tr><td><code>{{.Key}}</code></td><td>{{.Type}}</td><td>{{if .Value}}<code>{{.Value}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+  </tbody>
+</table>
+`))
+
+// DescribeSettings fills the description of each setting with the comment written above its property.
+func DescribeSettings(settings []Setting, comments []PropertyComment) {
+	for i := range settings {
+		index := slices.IndexFunc(comments, func(comment PropertyComment) bool { return slices.Equal(comment.Path, settings[i].Path) })
+		if index >= 0 {
+			settings[i].Description = comments[index].Text
+		}
+	}
+}
+
+// FormatDocs writes the reference table of the settings : environment variable, type, default and description.
+func FormatDocs(w io.Writer, format string, title string, settings []Setting) error {
+	switch format {
+	case DocsFormatMarkdown:
+		fmt.Fprintf(w, "# %s\n\n", title)
+		fmt.Fprintln(w, "| Environment variable | Type | Default | Description |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, setting := range settings {
+			defaultValue := ""
+			if setting.Value != "" {
+				defaultValue = "`" + markdownCell(setting.Value) + "`"
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", setting.Key, setting.Type, defaultValue, markdownCell(setting.Description))
+		}
+		return nil
+	case DocsFormatHTML:
+		return docsHTMLTemplate.Execute(w, struct {
+			Title    string
+			Settings []Setting
+		}{title, settings})
+	}
+
+	return fmt.Errorf("unknown docs format %q, expected one of : %s", format, strings.Join(DocsFormats, ", "))
+}
+
+// markdownCell escapes the pipes and the line breaks that would end the table cell.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", "<br>")
+}
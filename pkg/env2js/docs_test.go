@@ -0,0 +1,58 @@
+package env2js_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Docs", func() {
+	var settings []env2js.Setting
+	BeforeEach(func() {
+		settings = []env2js.Setting{
+			{Key: "AppSettings_isServed", Path: []string{"isServed"}, Value: "true", Type: "boolean"},
+			{Key: "AppSettings_API_apiRoot", Path: []string{"API", "apiRoot"}, Value: "url/server/app", Type: "string"},
+		}
+	})
+
+	Describe("DescribeSettings", func() {
+		It("should fill the descriptions from the comments", func() {
+			// Act
+			env2js.DescribeSettings(settings, []env2js.PropertyComment{{Path: []string{"API", "apiRoot"}, Text: "Base URL of the orders API"}})
+			// Assert
+			Expect(settings[0].Description).To(BeEmpty())
+			Expect(settings[1].Description).To(Equal("Base URL of the orders API"))
+		})
+	})
+
+	Describe("FormatDocs", func() {
+		var buffer *bytes.Buffer
+		BeforeEach(func() {
+			buffer = new(bytes.Buffer)
+			settings[1].Description = "Base URL | orders API\n<v2>"
+		})
+
+		It("should render a markdown table", func() {
+			Expect(env2js.FormatDocs(buffer, env2js.DocsFormatMarkdown, "AppSettings", settings)).To(Succeed())
+			Expect(buffer.String()).To(Equal("# AppSettings\n\n" +
+				"| Environment variable | Type | Default | Description |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| `AppSettings_isServed` | boolean | `true` |  |\n" +
+				"| `AppSettings_API_apiRoot` | string | `url/server/app` | Base URL \\| orders API<br><v2> |\n"))
+		})
+
+		It("should render an escaped HTML table", func() {
+			Expect(env2js.FormatDocs(buffer, env2js.DocsFormatHTML, "AppSettings", settings)).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("<h1>AppSettings</h1>"))
+			Expect(buffer.String()).To(ContainSubstring("<tr><td><code>AppSettings_API_apiRoot</code></td><td>string</td><td><code>url/server/app</code></td><td>Base URL | orders API\n&lt;v2&gt;</td></tr>"))
+		})
+
+		It("should fail with an unknown format", func() {
+			Expect(env2js.FormatDocs(buffer, "pdf", "AppSettings", settings)).To(MatchError("unknown docs format \"pdf\", expected one of : markdown, html"))
+		})
+	})
+})
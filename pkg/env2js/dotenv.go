@@ -0,0 +1,180 @@
+package env2js
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	SettingsEnvFilesEnvKey string = "SETTINGS_ENV_FILES"
+)
+
+// ParseDotenv parses the content of a .env file into a "key=value" list, in order of appearance.
+//
+//	# Comment
+//	export KEY=value # inline comment
+//	SINGLE='no $escape, \n is kept as is'
+//	DOUBLE="escapes \n \t \" \\ and
+//	multi-line values"
+func ParseDotenv(content string) ([]string, error) {
+	p := &dotenvParser{content: strings.ReplaceAll(content, "\r\n", "\n"), line: 1}
+	entries := []string{}
+	for {
+		p.skipBlank()
+		if p.done() {
+			return entries, nil
+		}
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		line := p.line
+		key, value, err := p.entry()
+		if err != nil {
+			return nil, fmt.Errorf("line %d : %w", line, err)
+		}
+		entries = append(entries, key+"="+value)
+	}
+}
+
+type dotenvParser struct {
+	content string
+	offset  int
+	line    int
+}
+
+func (p *dotenvParser) done() bool {
+	return p.offset >= len(p.content)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.content[p.offset]
+}
+
+func (p *dotenvParser) advance() byte {
+	c := p.content[p.offset]
+	p.offset++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipBlank skips the spaces, tabs and empty lines.
+func (p *dotenvParser) skipBlank() {
+	for !p.done() && strings.IndexByte(" \t\n", p.peek()) >= 0 {
+		p.advance()
+	}
+}
+
+// skipSpaces skips the spaces and tabs of the current line.
+func (p *dotenvParser) skipSpaces() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.advance()
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.done() && p.advance() != '\n' {
+	}
+}
+
+// entry parses "[export] KEY = value" up to the end of the line.
+func (p *dotenvParser) entry() (string, string, error) {
+	key := p.word()
+	if key == "export" {
+		p.skipSpaces()
+		if !p.done() && p.peek() != '=' {
+			key = p.word()
+		}
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("missing variable name")
+	}
+
+	p.skipSpaces()
+	if p.done() || p.peek() != '=' {
+		return "", "", fmt.Errorf("missing = after %s", key)
+	}
+	p.advance()
+	p.skipSpaces()
+
+	var value string
+	var err error
+	if !p.done() && (p.peek() == '\'' || p.peek() == '"') {
+		if value, err = p.quoted(p.advance()); err != nil {
+			return "", "", fmt.Errorf("%s : %w", key, err)
+		}
+		// Only a comment may follow the closing quote
+		p.skipSpaces()
+		if !p.done() && p.peek() != '\n' && p.peek() != '#' {
+			return "", "", fmt.Errorf("%s : unexpected characters after the closing quote", key)
+		}
+		p.skipLine()
+	} else {
+		value = p.unquoted()
+	}
+
+	return key, value, nil
+}
+
+// word reads a variable name, up to a space, a tab, an equal sign or the end of the line.
+func (p *dotenvParser) word() string {
+	start := p.offset
+	for !p.done() && strings.IndexByte(" \t\n=", p.peek()) < 0 {
+		p.advance()
+	}
+	return p.content[start:p.offset]
+}
+
+// unquoted reads the value up to the end of the line, without the inline comment and the trailing spaces.
+func (p *dotenvParser) unquoted() string {
+	start := p.offset
+	end := -1
+	for !p.done() && p.peek() != '\n' {
+		// An inline comment starts with a # preceded by a space
+		if end < 0 && p.peek() == '#' && (p.offset == start || strings.IndexByte(" \t", p.content[p.offset-1]) >= 0) {
+			end = p.offset
+		}
+		p.advance()
+	}
+	if end < 0 {
+		end = p.offset
+	}
+	p.skipLine()
+
+	return strings.TrimRight(p.content[start:end], " \t")
+}
+
+// quoted reads the value up to the closing quote, possibly over several lines.
+// Single quoted values are kept as is, double quoted values support the \n, \r, \t, \", \\ and \$ escapes.
+func (p *dotenvParser) quoted(quote byte) (string, error) {
+	value := strings.Builder{}
+	for !p.done() {
+		c := p.advance()
+		switch {
+		case c == quote:
+			return value.String(), nil
+		case c == '\\' && quote == '"' && !p.done():
+			escaped := p.advance()
+			switch escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(escaped)
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(escaped)
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("missing closing quote %c", quote)
+}
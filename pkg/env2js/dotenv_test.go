@@ -0,0 +1,45 @@
+package env2js_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Dotenv", func() {
+	Describe("ParseDotenv", func() {
+		It("should parse the comments, the export prefixes and the unquoted values", func() {
+			// Act
+			entries, err := env2js.ParseDotenv("# Comment\n\nexport AppSettings_A=1\r\nAppSettings_B = some value # inline comment\nAppSettings_C=a#b\nAppSettings_D=\n")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]string{"AppSettings_A=1", "AppSettings_B=some value", "AppSettings_C=a#b", "AppSettings_D="}))
+		})
+
+		It("should parse the quoted and multi-line values", func() {
+			// Act
+			entries, err := env2js.ParseDotenv("SINGLE='raw \\n $value' # comment\nDOUBLE=\"a\\tb \\\"c\\\" \\\\ \\$d\"\nMULTI=\"line 1\nline 2\"\nHASH='# not a comment'")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]string{
+				"SINGLE=raw \\n $value",
+				"DOUBLE=a\tb \"c\" \\ $d",
+				"MULTI=line 1\nline 2",
+				"HASH=# not a comment",
+			}))
+		})
+
+		DescribeTable("should report the line of the invalid entries",
+			func(content string, expectedError string) {
+				_, err := env2js.ParseDotenv(content)
+				Expect(err).To(MatchError(expectedError))
+			},
+			Entry("missing equal sign", "A=1\nB", "line 2 : missing = after B"),
+			Entry("missing closing quote", "A=\"1\n\nB=2", "line 1 : A : missing closing quote \""),
+			Entry("text after the closing quote", "A='1' 2", "line 1 : A : unexpected characters after the closing quote"),
+			Entry("missing name", "=1", "line 1 : missing variable name"),
+		)
+	})
+})
@@ -0,0 +1,459 @@
+// Package env2js applies environment variables to the settings object of a built JavaScript application,
+// eg : AppSettings_API_apiRoot=https://example.com/api overrides the apiRoot property of
+//
+//	const AppSettings = {API: {apiRoot: 'url/server/app'}};
+//
+// Apply updates a settings file on disk, Transform updates the content of a settings file held in memory.
+package env2js
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// Options locate the settings file, list the sources of the values and the checks to run.
+// The dependencies default to the ones of the process : its environment, the disk and a silent logger.
+type Options struct {
+	// Location of the settings file : the first file of the folder whose name starts with the prefix,
+	// or the file itself, the folder then only being the root of the output layout, the directory of the file by default
+	Folder string
+	Prefix string
+	File   string
+	// Name of the settings variable inside the file, eg : AppSettings
+	Variable string
+
+	// Template mode : overrides are always applied to the pristine copy of the settings file
+	Template    bool
+	TemplateDir string
+
+	// Output mode : updated files are written in a separate directory instead of in place
+	OutputDir  string
+	CopyAssets bool
+
+	// Only report the changes, without writing anything
+	DryRun bool
+	// Only record the settings and their defaults, without reading, decrypting nor interpolating any value, see Read
+	DefaultsOnly bool
+
+	// Strict mode : fail on unknown or missing overrides
+	Strict       bool
+	RequiredKeys StringList
+
+	// .env files and secrets directories read before the process environment
+	EnvFiles    StringList
+	SecretsDirs StringList
+	// Values given directly, eg : AppSettings_API_apiRoot=https://example.com/api, highest precedence by default
+	Sets StringList
+	// Order of the value sources, highest precedence first, see ValueSources
+	Precedence StringList
+	// Value sources replacing the ones of the precedence, the first one taking precedence, eg : a remote store
+	Sources Sources
+	// Log the source of every applied value
+	Verbose bool
+	// Expand the ${NAME} references of the values, see Interpolator
+	Interpolate bool
+	// JSON file of the transforms by environment variable name, see ReadTransformsFile
+	TransformsFile string
+	// Transforms available to the chains by name, the built-in ones when nil, see BuiltinTransforms
+	Transforms Transformers
+	// KV v2 secret of the vault source, see VaultConfig
+	VaultAddr  string
+	VaultMount string
+	VaultPath  string
+	// File of the age identities decrypting the values, see Decrypter
+	AgeKeyFile string
+	// Keys of the kv source, see KVConfig
+	KVAddr    string
+	KVPrefix  string
+	KVTimeout time.Duration
+	KVRetries int
+
+	// Glob patterns of the environment variable names whose values are masked, see MatchesSecretPattern
+	SecretPatterns StringList
+
+	// Validation of the patched settings : JSON Schema file and inline constraints
+	SchemaFile  string
+	Constraints StringList
+
+	// Dependencies, the ones of the process when nil
+	Getenv     func(key string) string
+	Environ    func() []string
+	FileSystem FileSystem
+	HTTPClient *http.Client
+	// Logger of the warnings and of the applied values, silent when nil
+	Logger *slog.Logger
+}
+
+// FileSystem reads and writes the settings files, the secret files and the .env files.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(path string, perm fs.FileMode) error
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// OSFileSystem is the file system of the process.
+type OSFileSystem struct{}
+
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OSFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OSFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// fileSystemOrOS returns the file system, the one of the process when nil.
+func fileSystemOrOS(fsys FileSystem) FileSystem {
+	if fsys == nil {
+		return OSFileSystem{}
+	}
+
+	return fsys
+}
+
+// Report describes the settings file and the values applied to it.
+type Report struct {
+	SettingsFilePath string
+	// Where the updated settings file was written, empty when nothing was written
+	OutputFilePath string
+
+	// Every overridable property, with its description and its default value
+	Settings []Setting
+	// Values applied to the properties, including the unchanged ones
+	Changes []Change
+	// Variables of the settings that did not match any property
+	Unused []UnusedOverride
+}
+
+// withDefaults fills the missing dependencies with the ones of the process, and the folder of the file option.
+func (o Options) withDefaults() Options {
+	if o.File != "" && o.Folder == "" {
+		o.Folder = filepath.Dir(o.File)
+	}
+	if o.Getenv == nil {
+		o.Getenv = os.Getenv
+	}
+	if o.Environ == nil {
+		o.Environ = os.Environ
+	}
+	o.FileSystem = fileSystemOrOS(o.FileSystem)
+	if o.Logger == nil {
+		o.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return o
+}
+
+// SettingsFilePath returns the settings file : the file option, or the first file of the folder starting with the prefix.
+func (o Options) SettingsFilePath() (string, error) {
+	if o.File != "" {
+		return o.File, nil
+	}
+	if o.Folder == "" || o.Prefix == "" {
+		return "", WrapError(ErrMissingConfig, errors.New("missing settings file, set the file or both the folder and the prefix"))
+	}
+
+	fileList, err := CandidateFilePaths(o.withDefaults().FileSystem, o.Folder, o.Prefix)
+	if err != nil {
+		return "", WrapError(ErrUsage, err)
+	}
+	if len(fileList) == 0 {
+		return "", WrapError(ErrNoFileMatched, errors.New("No file found with pattern: "+filepath.Join(o.Folder, o.Prefix)+"*.js"))
+	}
+
+	return fileList[0], nil
+}
+
+// Apply applies the values to the settings file, then writes it in place or in the output directory.
+// Nothing is written when one of the checks fails, nor in dry run.
+func Apply(ctx context.Context, options Options) (Report, error) {
+	options = options.withDefaults()
+	settingsFilePath, err := options.SettingsFilePath()
+	if err != nil {
+		return Report{}, err
+	}
+	outputFilePath, err := OutputFilePath(options.Folder, settingsFilePath, options.OutputDir)
+	if err != nil {
+		return Report{}, WrapError(ErrUsage, err)
+	}
+
+	// Read the JavaScript file, or its pristine copy in template mode.
+	// The source file is never modified in output mode : there is no need for a pristine copy.
+	var jsBytes []byte
+	switch {
+	case options.DryRun:
+		jsBytes, err = readSettingsFile(settingsFilePath, options)
+	case options.Template && outputFilePath == settingsFilePath:
+		var saved bool
+		if jsBytes, saved, err = ReadOriginalFile(options.FileSystem, settingsFilePath, options.TemplateDir); saved {
+			options.Logger.Info("✓ Saved pristine copy : " + OriginalFilePath(settingsFilePath, options.TemplateDir))
+		}
+	default:
+		jsBytes, err = options.FileSystem.ReadFile(settingsFilePath)
+	}
+	if err != nil {
+		return Report{}, readError(err)
+	}
+
+	jsBytes, report, err := transform(ctx, settingsFilePath, jsBytes, options)
+	if err != nil || options.DryRun {
+		return report, err
+	}
+
+	if options.OutputDir != "" && options.CopyAssets {
+		if err := CopyAssets(options.FileSystem, options.Folder, options.OutputDir, settingsFilePath); err != nil {
+			return report, WrapError(ErrWrite, err)
+		}
+	}
+	if outputFilePath != settingsFilePath {
+		if err := options.FileSystem.MkdirAll(filepath.Dir(outputFilePath), fs.ModePerm); err != nil {
+			return report, WrapError(ErrWrite, err)
+		}
+	}
+	if err := options.FileSystem.WriteFile(outputFilePath, jsBytes, fs.ModePerm); err != nil {
+		return report, WrapError(ErrWrite, err)
+	}
+	report.OutputFilePath = outputFilePath
+	LogChanges(options.Logger, outputFilePath, report.Changes)
+
+	options.Logger.Info("🎉 Successfuly updated : " + outputFilePath + " 🎉")
+	return report, nil
+}
+
+// Transform applies the values to the content of a settings file, eg : a file served from memory.
+// The file option only names the content in the errors and the logs.
+func Transform(src []byte, options Options) ([]byte, Report, error) {
+	options = options.withDefaults()
+	settingsFilePath := options.File
+	if settingsFilePath == "" {
+		settingsFilePath = options.Variable + ".js"
+	}
+
+	return transform(context.Background(), settingsFilePath, src, options)
+}
+
+func transform(ctx context.Context, settingsFilePath string, jsBytes []byte, options Options) ([]byte, Report, error) {
+	ast, walker, err := walk(ctx, settingsFilePath, jsBytes, options)
+	if err != nil {
+		return nil, Report{}, err
+	}
+	report := newReport(settingsFilePath, walker, jsBytes)
+	if err := check(ast, walker, options); err != nil {
+		return nil, report, err
+	}
+
+	var buffer bytes.Buffer
+	ast.JS(&buffer)
+	return buffer.Bytes(), report, nil
+}
+
+// Read applies the values to the settings file without checking nor writing anything, eg : to compare the file
+// with the values. With the DefaultsOnly option, no value source is read, eg : to list the settings.
+func Read(ctx context.Context, options Options) (Report, error) {
+	options = options.withDefaults()
+	settingsFilePath, err := options.SettingsFilePath()
+	if err != nil {
+		return Report{}, err
+	}
+	jsBytes, err := readSettingsFile(settingsFilePath, options)
+	if err != nil {
+		return Report{}, readError(err)
+	}
+
+	_, walker, err := walk(ctx, settingsFilePath, jsBytes, options)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return newReport(settingsFilePath, walker, jsBytes), nil
+}
+
+// GenerateSchema infers the schema of the settings object from its default values and its comments.
+func GenerateSchema(ctx context.Context, options Options) (*Schema, error) {
+	options = options.withDefaults()
+	settingsFilePath, err := options.SettingsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	jsBytes, err := readSettingsFile(settingsFilePath, options)
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	// The defaults are read from a syntax tree left untouched by the values
+	options.DefaultsOnly = true
+	if _, _, err := walk(ctx, settingsFilePath, jsBytes, options); err != nil {
+		return nil, err
+	}
+	ast, err := ParseJS(jsBytes)
+	if err != nil {
+		return nil, WrapError(ErrParse, NewParseError(settingsFilePath, jsBytes, err))
+	}
+	object := SettingsObject(ast, options.Variable)
+	if object == nil {
+		return nil, WrapError(ErrVariableNotFound, fmt.Errorf("settings variable %s is not assigned an object literal in %s", options.Variable, settingsFilePath))
+	}
+
+	return InferSchema(object, options.Variable, PropertyComments(jsBytes, options.Variable)), nil
+}
+
+// Restore puts the pristine copy of the settings file back, see Options.Template.
+func Restore(options Options) (string, error) {
+	options = options.withDefaults()
+	settingsFilePath, err := options.SettingsFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return settingsFilePath, RestoreOriginalFile(options.FileSystem, settingsFilePath, options.TemplateDir)
+}
+
+// readSettingsFile reads the settings file, or its pristine copy in template mode when there is one.
+// Unlike Apply, the dry runs and the other functions never save the pristine copy.
+func readSettingsFile(settingsFilePath string, options Options) ([]byte, error) {
+	if options.Template {
+		return PeekOriginalFile(options.FileSystem, settingsFilePath, options.TemplateDir)
+	}
+
+	return options.FileSystem.ReadFile(settingsFilePath)
+}
+
+// readError attaches a kind to the errors reading the settings file : ErrNoFileMatched when it does not exist,
+// ErrMissingConfig otherwise.
+func readError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return wrapErrorKind(ErrNoFileMatched, err)
+	}
+
+	return wrapErrorKind(ErrMissingConfig, err)
+}
+
+// walk parses the settings file then applies the values to its syntax tree.
+func walk(ctx context.Context, settingsFilePath string, jsBytes []byte, options Options) (*js.AST, *Walker, error) {
+	if options.Variable == "" {
+		return nil, nil, WrapError(ErrMissingConfig, errors.New("missing settings variable name"))
+	}
+
+	// Parse the JavaScript file
+	ast, err := ParseJS(jsBytes)
+	if err != nil {
+		return nil, nil, WrapError(ErrParse, NewParseError(settingsFilePath, jsBytes, err))
+	}
+
+	// Analyse du code javascript et réalisation des modifications si nécessaire
+	var decrypter *Decrypter
+	sources := Sources{}
+	if !options.DefaultsOnly {
+		decrypter = NewDecrypter(options)
+		if sources, err = ValueSources(ctx, options, decrypter); err != nil {
+			return nil, nil, err
+		}
+	}
+	walker := &Walker{SettingVariableName: options.Variable, Overrides: NewSourceOverrides(options.Variable, sources)}
+	walker.Overrides.FileSystem = options.FileSystem
+	walker.Overrides.Decrypter = decrypter
+	walker.Overrides.Interpolate = options.Interpolate && !options.DefaultsOnly
+	walker.Overrides.Transformers = options.Transforms
+	if options.TransformsFile != "" && !options.DefaultsOnly {
+		if walker.Overrides.Transforms, err = ReadTransformsFile(options.FileSystem, options.TransformsFile, walker.Overrides.Prefix, options.Transforms); err != nil {
+			return nil, nil, WrapError(ErrMissingConfig, err)
+		}
+	}
+	js.Walk(walker, ast)
+	if err := walker.Overrides.Err(); err != nil {
+		return nil, nil, err
+	}
+	if !walker.Found {
+		var candidateFilePaths []string
+		if options.Prefix != "" {
+			candidateFilePaths, _ = CandidateFilePaths(options.FileSystem, options.Folder, options.Prefix)
+		}
+		return nil, nil, WrapError(ErrVariableNotFound, VariableNotFoundError(options.FileSystem, settingsFilePath, options.Variable, ast, candidateFilePaths))
+	}
+
+	walker.SecretPatterns = options.SecretPatterns
+	if len(walker.SecretPatterns) == 0 {
+		walker.SecretPatterns = DefaultSecretPatterns
+	}
+	annotations, err := SettingsAnnotations(PropertyComments(jsBytes, options.Variable), walker.Overrides.Prefix)
+	if err != nil {
+		return nil, nil, WrapError(ErrParse, err)
+	}
+	walker.Annotate(annotations)
+
+	return ast, walker, nil
+}
+
+// check logs the findings of the walk, then checks the strict mode and the validation rules.
+func check(ast *js.AST, walker *Walker, options Options) error {
+	LogUnusedOverrides(options.Logger, walker.Overrides.UnusedOverrides(walker.Keys()))
+	LogEmbeddedSecrets(options.Logger, walker.Changes)
+	if options.Verbose {
+		LogOrigins(options.Logger, walker.Changes)
+	}
+	if options.Strict {
+		if err := CheckStrict(walker, options.RequiredKeys); err != nil {
+			return WrapError(ErrValidation, err)
+		}
+	}
+
+	return ValidateConfigFile(ast, walker, options)
+}
+
+func newReport(settingsFilePath string, walker *Walker, jsBytes []byte) Report {
+	DescribeSettings(walker.Settings, PropertyComments(jsBytes, walker.SettingVariableName))
+	return Report{
+		SettingsFilePath: settingsFilePath,
+		Settings:         walker.Settings,
+		Changes:          walker.Changes,
+		Unused:           walker.Overrides.UnusedOverrides(walker.Keys()),
+	}
+}
+
+// LogUnusedOverrides warns about the environment variables that did not match any property.
+func LogUnusedOverrides(logger *slog.Logger, unusedOverrides []UnusedOverride) {
+	for _, unusedOverride := range unusedOverrides {
+		if len(unusedOverride.Suggestions) == 0 {
+			logger.Warn("⚠ Unused environment variable : " + unusedOverride.Key)
+			continue
+		}
+		logger.Warn("⚠ Unused environment variable : " + unusedOverride.Key + " (did you mean " + strings.Join(unusedOverride.Suggestions, ", ") + " ?)")
+	}
+}
+
+// LogChanges logs every value applied to the settings file, with structured fields.
+func LogChanges(logger *slog.Logger, settingsFilePath string, changes []Change) {
+	for _, change := range changes {
+		if change.OldValue == change.NewValue {
+			continue
+		}
+		logger.Info("Applied "+change.Key,
+			"file", settingsFilePath,
+			"path", PropertyPath(change.Path),
+			"key", change.Key,
+			"value", MaskValue(change.NewValue, change.Secret),
+			"origin", change.Origin,
+		)
+	}
+}
@@ -0,0 +1,49 @@
+package env2js
+
+import (
+	"errors"
+)
+
+// Kinds of errors, tell them apart with errors.Is, eg : errors.Is(err, ErrWrite)
+var (
+	ErrUsage            = errors.New("invalid options")
+	ErrMissingConfig    = errors.New("missing configuration")
+	ErrNoFileMatched    = errors.New("no settings file matched")
+	ErrVariableNotFound = errors.New("settings variable not found")
+	ErrParse            = errors.New("cannot parse the settings file")
+	ErrValidation       = errors.New("invalid settings")
+	ErrWrite            = errors.New("cannot write the settings file")
+)
+
+// Error attaches a kind to an error, keeping its message : errors.Is(err, ErrWrite) tells the kind.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+// WrapError attaches the kind to the error, nil staying nil.
+func WrapError(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+// wrapErrorKind attaches the kind to the error, unless it already has one.
+func wrapErrorKind(kind error, err error) error {
+	var kindError *Error
+	if errors.As(err, &kindError) {
+		return err
+	}
+
+	return WrapError(kind, err)
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
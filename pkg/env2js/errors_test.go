@@ -0,0 +1,30 @@
+package env2js_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Errors", func() {
+	Describe("WrapError", func() {
+		It("should keep the message and the wrapped error", func() {
+			// Arrange
+			cause := errors.New("permission denied")
+			// Act
+			err := env2js.WrapError(env2js.ErrWrite, cause)
+			// Assert
+			Expect(err).To(MatchError("permission denied"))
+			Expect(errors.Is(err, env2js.ErrWrite)).To(BeTrue())
+			Expect(errors.Is(err, cause)).To(BeTrue())
+		})
+
+		It("should keep nil errors nil", func() {
+			Expect(env2js.WrapError(env2js.ErrWrite, nil)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,154 @@
+package env2js
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	SettingsInterpolateEnvKey string = "SETTINGS_INTERPOLATE"
+
+	// Origin of the string literals of the settings file expanded by the interpolation
+	InterpolationSourceName string = "interpolation"
+)
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Interpolator expands the shell-style references of the values :
+// ${NAME}, ${NAME:-default}, ${NAME:?message} and $$ for a literal $.
+// The other $ are kept as is.
+type Interpolator struct {
+	// Lookup returns the raw value of a variable, its references are expanded in turn
+	Lookup func(name string) (string, bool)
+
+	// Variables being expanded, to detect the cycles
+	stack []string
+}
+
+// InterpolationSyntaxError locates a malformed reference by its byte offset, never quoting the value, which may be a secret.
+type InterpolationSyntaxError struct {
+	Reason string
+	Offset int
+	// Variable whose value holds the reference, empty for the value being expanded
+	Variable string
+}
+
+func (e *InterpolationSyntaxError) Error() string {
+	if e.Variable == "" {
+		return fmt.Sprintf("%s at offset %d", e.Reason, e.Offset)
+	}
+
+	return fmt.Sprintf("%s at offset %d of %s", e.Reason, e.Offset, e.Variable)
+}
+
+// Expand expands the references of the value.
+func (i *Interpolator) Expand(value string) (string, error) {
+	return i.expand(value, 0)
+}
+
+// expand expands the references of the value found at the offset of the value being expanded.
+func (i *Interpolator) expand(value string, offset int) (string, error) {
+	result := strings.Builder{}
+	for index := 0; index < len(value); index++ {
+		if value[index] != '$' || index+1 == len(value) {
+			result.WriteByte(value[index])
+			continue
+		}
+
+		switch value[index+1] {
+		case '$':
+			result.WriteByte('$')
+			index++
+		case '{':
+			end := closingBrace(value, index+2)
+			if end < 0 {
+				return "", &InterpolationSyntaxError{Reason: "unterminated ${", Offset: offset + index}
+			}
+			expanded, err := i.expression(value[index+2:end], offset+index)
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(expanded)
+			index = end
+		default:
+			result.WriteByte('$')
+		}
+	}
+
+	return result.String(), nil
+}
+
+// ExpandVariable expands the value of the named variable, which cannot reference itself.
+func (i *Interpolator) ExpandVariable(name string, value string) (string, error) {
+	if slices.Contains(i.stack, name) {
+		return "", fmt.Errorf("interpolation cycle %s", strings.Join(append(slices.Clone(i.stack), name), " → "))
+	}
+
+	i.stack = append(i.stack, name)
+	defer func() { i.stack = i.stack[:len(i.stack)-1] }()
+
+	return i.Expand(value)
+}
+
+// expression expands the content of the ${...} found at the offset.
+func (i *Interpolator) expression(expression string, offset int) (string, error) {
+	name, operator, word := expression, "", ""
+	if index := strings.Index(expression, ":"); index >= 0 {
+		name, operator = expression[:index], expression[index:min(index+2, len(expression))]
+		word = expression[min(index+2, len(expression)):]
+	}
+	if !variableNamePattern.MatchString(name) || (operator != "" && operator != ":-" && operator != ":?") {
+		return "", &InterpolationSyntaxError{Reason: "invalid expression", Offset: offset}
+	}
+
+	// Empty variables are unset ones
+	if value, ok := i.Lookup(name); ok && value != "" {
+		expanded, err := i.ExpandVariable(name, value)
+		var syntaxError *InterpolationSyntaxError
+		if errors.As(err, &syntaxError) && syntaxError.Variable == "" {
+			syntaxError.Variable = name
+		}
+		return expanded, err
+	}
+
+	wordOffset := offset + 2 + len(expression) - len(word)
+	switch operator {
+	case ":-":
+		return i.expand(word, wordOffset)
+	case ":?":
+		message, err := i.expand(word, wordOffset)
+		if err != nil {
+			return "", err
+		}
+		if message == "" {
+			return "", WrapError(ErrMissingConfig, fmt.Errorf("%s is not set", name))
+		}
+		return "", WrapError(ErrMissingConfig, fmt.Errorf("%s : %s", name, message))
+	}
+
+	return "", nil
+}
+
+// closingBrace returns the index of the } closing the ${ opened before start, or -1.
+func closingBrace(value string, start int) int {
+	depth := 1
+	for index := start; index < len(value); index++ {
+		switch {
+		case value[index] == '$' && index+1 < len(value) && value[index+1] == '$':
+			index++
+		case value[index] == '$' && index+1 < len(value) && value[index+1] == '{':
+			depth++
+			index++
+		case value[index] == '}':
+			depth--
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,127 @@
+package env2js
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	KeysFormatList      string = "list"
+	KeysFormatEnv       string = "env"
+	KeysFormatCompose   string = "compose"
+	KeysFormatConfigMap string = "configmap"
+	KeysFormatK8sEnv    string = "k8s-env"
+)
+
+var KeysFormats = []string{KeysFormatEnv, KeysFormatCompose, KeysFormatConfigMap, KeysFormatK8sEnv, KeysFormatList}
+
+// FormatKeys writes every overridable environment variable with its default value and type :
+//   - list : the names only
+//   - env : a .env file
+//   - compose : the environment section of a docker-compose service
+//   - configmap : a Kubernetes ConfigMap named after name
+//   - k8s-env : the env section of a Kubernetes container
+func FormatKeys(w io.Writer, format string, settings []Setting, name string) error {
+	switch format {
+	case KeysFormatList:
+		for _, setting := range settings {
+			fmt.Fprintln(w, setting.Key)
+		}
+	case KeysFormatEnv:
+		for _, setting := range settings {
+			fmt.Fprintf(w, "# %s\n%s=%s\n", setting.Type, setting.Key, DotenvValue(setting.Value))
+		}
+	case KeysFormatCompose:
+		fmt.Fprintln(w, "environment:")
+		for _, setting := range settings {
+			fmt.Fprintf(w, "  # %s\n  %s: %s\n", setting.Type, setting.Key, ComposeValue(setting.Value))
+		}
+	case KeysFormatConfigMap:
+		fmt.Fprintf(w, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n", name)
+		for _, setting := range settings {
+			fmt.Fprintf(w, "  # %s\n  %s: %s\n", setting.Type, setting.Key, YAMLString(setting.Value))
+		}
+	case KeysFormatK8sEnv:
+		fmt.Fprintln(w, "env:")
+		for _, setting := range settings {
+			fmt.Fprintf(w, "  # %s\n  - name: %s\n    value: %s\n", setting.Type, setting.Key, YAMLString(setting.Value))
+		}
+	default:
+		return fmt.Errorf("unknown keys format %q, expected one of : %s", format, strings.Join(KeysFormats, ", "))
+	}
+
+	return nil
+}
+
+// DotenvValue quotes the value when it would not be read back as is from a .env file.
+func DotenvValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"'#$\\=") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// YAMLString writes the value as a YAML double-quoted scalar, escaping the characters YAML cannot hold as is.
+func YAMLString(value string) string {
+	quoted := strings.Builder{}
+	quoted.WriteByte('"')
+	for index, r := range value {
+		switch {
+		case r == utf8.RuneError && !strings.HasPrefix(value[index:], string(utf8.RuneError)):
+			// Invalid UTF-8, unreadable by the YAML parsers
+			quoted.WriteString(`\uFFFD`)
+		case r == '"' || r == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteRune(r)
+		case yamlEscapes[r] != "":
+			quoted.WriteString(yamlEscapes[r])
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) || r == 0xfeff:
+			fmt.Fprintf(&quoted, `\u%04X`, r)
+		default:
+			quoted.WriteRune(r)
+		}
+	}
+	quoted.WriteByte('"')
+
+	return quoted.String()
+}
+
+// Escapes of the YAML double-quoted scalars
+var yamlEscapes = map[rune]string{
+	0x00:   `\0`,
+	'\a':   `\a`,
+	'\b':   `\b`,
+	'\t':   `\t`,
+	'\n':   `\n`,
+	'\v':   `\v`,
+	'\f':   `\f`,
+	'\r':   `\r`,
+	0x1b:   `\e`,
+	0x85:   `\N`,
+	0xa0:   `\_`,
+	0x2028: `\L`,
+	0x2029: `\P`,
+}
+
+// ComposeValue writes the value of a docker-compose environment variable, its $ being escaped from the interpolation.
+func ComposeValue(value string) string {
+	return YAMLString(strings.ReplaceAll(value, "$", "$$"))
+}
+
+// ConfigMapName derives a Kubernetes resource name from the settings variable name.
+func ConfigMapName(settingsVariableName string) string {
+	name := strings.Builder{}
+	for _, r := range strings.ToLower(settingsVariableName) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			name.WriteRune(r)
+		} else {
+			name.WriteRune('-')
+		}
+	}
+
+	return strings.Trim(name.String(), "-")
+}
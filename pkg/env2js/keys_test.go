@@ -0,0 +1,91 @@
+package env2js_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("Keys", func() {
+	settings := []env2js.Setting{
+		{Key: "AppSettings_isServed", Path: []string{"isServed"}, Value: "true", Type: "boolean"},
+		{Key: "AppSettings_API_apiRoot", Path: []string{"API", "apiRoot"}, Value: "url/server app", Type: "string"},
+	}
+
+	Describe("FormatKeys", func() {
+		var buffer *bytes.Buffer
+		BeforeEach(func() {
+			buffer = new(bytes.Buffer)
+		})
+
+		It("should generate a .env template", func() {
+			Expect(env2js.FormatKeys(buffer, env2js.KeysFormatEnv, settings, "appsettings")).To(Succeed())
+			Expect(buffer.String()).To(Equal("# boolean\nAppSettings_isServed=true\n# string\nAppSettings_API_apiRoot=\"url/server app\"\n"))
+		})
+
+		It("should generate a docker-compose environment section", func() {
+			Expect(env2js.FormatKeys(buffer, env2js.KeysFormatCompose, settings, "appsettings")).To(Succeed())
+			Expect(buffer.String()).To(Equal("environment:\n" +
+				"  # boolean\n  AppSettings_isServed: \"true\"\n" +
+				"  # string\n  AppSettings_API_apiRoot: \"url/server app\"\n"))
+		})
+
+		It("should generate a Kubernetes ConfigMap", func() {
+			Expect(env2js.FormatKeys(buffer, env2js.KeysFormatConfigMap, settings, "appsettings")).To(Succeed())
+			Expect(buffer.String()).To(Equal("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: appsettings\ndata:\n" +
+				"  # boolean\n  AppSettings_isServed: \"true\"\n" +
+				"  # string\n  AppSettings_API_apiRoot: \"url/server app\"\n"))
+		})
+
+		It("should generate a Kubernetes env section", func() {
+			Expect(env2js.FormatKeys(buffer, env2js.KeysFormatK8sEnv, settings, "appsettings")).To(Succeed())
+			Expect(buffer.String()).To(Equal("env:\n" +
+				"  # boolean\n  - name: AppSettings_isServed\n    value: \"true\"\n" +
+				"  # string\n  - name: AppSettings_API_apiRoot\n    value: \"url/server app\"\n"))
+		})
+
+		It("should escape the dollars of the docker-compose values from the interpolation", func() {
+			// Arrange
+			dollarSettings := []env2js.Setting{{Key: "AppSettings_price", Value: "5$ or ${PRICE}", Type: "string"}}
+			// Act
+			Expect(env2js.FormatKeys(buffer, env2js.KeysFormatCompose, dollarSettings, "appsettings")).To(Succeed())
+			// Assert
+			Expect(buffer.String()).To(Equal("environment:\n  # string\n  AppSettings_price: \"5$$ or $${PRICE}\"\n"))
+		})
+
+		It("should fail with an unknown format", func() {
+			Expect(env2js.FormatKeys(buffer, "xml", settings, "appsettings")).To(MatchError("unknown keys format \"xml\", expected one of : env, compose, configmap, k8s-env, list"))
+		})
+	})
+
+	DescribeTable("YAMLString",
+		func(value string, expected string) {
+			// Act
+			quoted := env2js.YAMLString(value)
+			// Assert
+			Expect(quoted).To(Equal(expected))
+			var parsed string
+			Expect(yaml.Unmarshal([]byte("value: "+quoted), &struct{ Value *string }{&parsed})).To(Succeed())
+			Expect(parsed).To(Equal(value))
+		},
+		Entry("plain", "url/server app", `"url/server app"`),
+		Entry("quotes and backslashes", `say "hi" \o/`, `"say \"hi\" \\o/"`),
+		Entry("line breaks", "line1\nline2\r\n", `"line1\nline2\r\n"`),
+		Entry("control characters", "a\x00b\x1bc\x7f", `"a\0b\ec\u007F"`),
+		Entry("unicode", "é ✓ 🎉", `"é ✓ 🎉"`),
+		Entry("unicode line separators", "a\u2028b\u0085c", `"a\Lb\Nc"`),
+		Entry("dollar", "$HOME", `"$HOME"`),
+	)
+
+	Describe("ConfigMapName", func() {
+		It("should derive a valid resource name", func() {
+			Expect(env2js.ConfigMapName("AppSettings")).To(Equal("appsettings"))
+			Expect(env2js.ConfigMapName("_App_Settings$")).To(Equal("app-settings"))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+package env2js
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	SettingsKVAddrEnvKey   string = "SETTINGS_KV_ADDR"
+	SettingsKVPrefixEnvKey string = "SETTINGS_KV_PREFIX"
+	KVTokenEnvKey          string = "CONSUL_HTTP_TOKEN"
+
+	DefaultKVTimeout = 5 * time.Second
+	DefaultKVRetries = 3
+	DefaultKVBackoff = 200 * time.Millisecond
+)
+
+// KVConfig locates the keys of an HTTP key-value store speaking the Consul API, eg : frontend/orders/
+type KVConfig struct {
+	Address string
+	Prefix  string
+	Token   string
+
+	// Timeout of every attempt, and number of retries after the first attempt,
+	// the delay between the attempts doubling from Backoff
+	Timeout time.Duration
+	Retries int
+	Backoff time.Duration
+
+	Client *http.Client
+}
+
+// kvPair is an entry of the Consul ?recurse listing, the value being base64 encoded.
+type kvPair struct {
+	Key   string
+	Value *string
+}
+
+// NewKVConfig reads the location of the keys from the options and the token from the environment.
+func NewKVConfig(options Options) KVConfig {
+	options = options.withDefaults()
+	kv := KVConfig{
+		Address: options.KVAddr,
+		Prefix:  options.KVPrefix,
+		Token:   options.Getenv(KVTokenEnvKey),
+		Timeout: options.KVTimeout,
+		Retries: options.KVRetries,
+		Backoff: DefaultKVBackoff,
+		Client:  options.HTTPClient,
+	}
+	if kv.Timeout == 0 {
+		kv.Timeout = DefaultKVTimeout
+	}
+
+	return kv
+}
+
+// NewKVSource lists the keys under the prefix and translates them into environment variable names :
+// frontend/orders/API/apiRoot becomes AppSettings_API_apiRoot. The folders and the keys without value are ignored.
+func NewKVSource(ctx context.Context, kv KVConfig, prefix string) (*MapSource, error) {
+	kvPrefix := strings.TrimLeft(kv.Prefix, "/")
+	if kvPrefix != "" && !strings.HasSuffix(kvPrefix, "/") {
+		kvPrefix += "/"
+	}
+	name := "kv " + kvPrefix
+	if kv.Address == "" {
+		return nil, WrapError(ErrMissingConfig, fmt.Errorf("%s : missing key-value store address, see -kv-addr or %s", name, SettingsKVAddrEnvKey))
+	}
+
+	pairs, err := kv.list(ctx, kvPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", name, err)
+	}
+
+	environ := []string{}
+	for _, pair := range pairs {
+		path := strings.TrimPrefix(pair.Key, kvPrefix)
+		if pair.Value == nil || path == "" || strings.HasSuffix(path, "/") {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(*pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s : invalid value of %s : %w", name, pair.Key, err)
+		}
+		environ = append(environ, prefix+strings.ReplaceAll(path, "/", "_")+"="+string(value))
+	}
+
+	return NewEnvironSource(name, environ), nil
+}
+
+// list fetches the keys under the prefix, retrying with backoff on network errors and server errors.
+func (kv KVConfig) list(ctx context.Context, kvPrefix string) ([]kvPair, error) {
+	client := kv.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	requestURL := strings.TrimRight(kv.Address, "/") + "/v1/kv/" + (&url.URL{Path: kvPrefix}).EscapedPath() + "?recurse=true"
+
+	var pairs []kvPair
+	var err error
+	backoff := kv.Backoff
+	for attempt := 0; attempt <= kv.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		pairs, retry, err = kv.get(ctx, client, requestURL)
+		if err == nil || !retry {
+			break
+		}
+	}
+
+	return pairs, err
+}
+
+// get runs one attempt within the timeout, and tells whether the failure is worth a retry.
+func (kv KVConfig) get(ctx context.Context, client *http.Client, requestURL string) ([]kvPair, bool, error) {
+	if kv.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, kv.Timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if kv.Token != "" {
+		request.Header.Set("X-Consul-Token", kv.Token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, true, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		// No key under the prefix
+		return []kvPair{}, false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError:
+		return nil, true, fmt.Errorf("GET %s : %s", requestURL, response.Status)
+	case response.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("GET %s : %s", requestURL, response.Status)
+	}
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, true, err
+	}
+	pairs := []kvPair{}
+	if err := json.Unmarshal(responseBytes, &pairs); err != nil {
+		return nil, false, fmt.Errorf("GET %s : invalid response : %w", requestURL, err)
+	}
+
+	return pairs, false, nil
+}
@@ -0,0 +1,150 @@
+package env2js_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+// kvFixture serves the Consul ?recurse listing of frontend/orders/, failing the first requests with the given status.
+func kvFixture(failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		if r.URL.Path != "/v1/kv/frontend/orders/" || r.URL.Query().Get("recurse") == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Consul-Token") != "kv-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// API/apiRoot = https://example.com/api, MyArray/[0] = a, title = Orders
+		_, _ = w.Write([]byte(`[
+			{"Key": "frontend/orders/", "Value": null},
+			{"Key": "frontend/orders/API/", "Value": null},
+			{"Key": "frontend/orders/API/apiRoot", "Value": "aHR0cHM6Ly9leGFtcGxlLmNvbS9hcGk="},
+			{"Key": "frontend/orders/MyArray/[0]", "Value": "YQ=="},
+			{"Key": "frontend/orders/title", "Value": "T3JkZXJz"}
+		]`))
+	}))
+
+	return server, requests
+}
+
+var _ = Describe("KV", func() {
+	kvConfig := func(server *httptest.Server) env2js.KVConfig {
+		return env2js.KVConfig{Address: server.URL, Prefix: "frontend/orders", Token: "kv-token", Timeout: time.Second, Retries: 2, Backoff: time.Millisecond}
+	}
+
+	Describe("NewKVSource", func() {
+		It("should translate the keys under the prefix into environment variable names", func() {
+			// Arrange
+			server, _ := kvFixture(0, 0)
+			defer server.Close()
+			// Act
+			source, err := env2js.NewKVSource(context.Background(), kvConfig(server), "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.Name()).To(Equal("kv frontend/orders/"))
+			Expect(source.List("AppSettings_")).To(Equal([]string{"AppSettings_API_apiRoot", "AppSettings_MyArray_[0]", "AppSettings_title"}))
+			value, _ := source.Lookup("AppSettings_API_apiRoot")
+			Expect(value).To(Equal("https://example.com/api"))
+		})
+
+		It("should retry the server errors with backoff", func() {
+			// Arrange
+			server, requests := kvFixture(2, http.StatusServiceUnavailable)
+			defer server.Close()
+			// Act
+			source, err := env2js.NewKVSource(context.Background(), kvConfig(server), "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.List("AppSettings_")).To(HaveLen(3))
+			Expect(requests.Load()).To(Equal(int32(3)))
+		})
+
+		It("should give up after the last retry", func() {
+			// Arrange
+			server, requests := kvFixture(10, http.StatusBadGateway)
+			defer server.Close()
+			// Act
+			_, err := env2js.NewKVSource(context.Background(), kvConfig(server), "AppSettings_")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("kv frontend/orders/ : GET " + server.URL + "/v1/kv/frontend/orders/?recurse=true : 502 Bad Gateway")))
+			Expect(requests.Load()).To(Equal(int32(3)))
+		})
+
+		It("should not retry the client errors", func() {
+			// Arrange
+			server, requests := kvFixture(0, 0)
+			defer server.Close()
+			config := kvConfig(server)
+			config.Token = ""
+			// Act
+			_, err := env2js.NewKVSource(context.Background(), config, "AppSettings_")
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("403 Forbidden")))
+			Expect(requests.Load()).To(Equal(int32(1)))
+		})
+
+		It("should time out the slow requests", func() {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}))
+			defer server.Close()
+			config := kvConfig(server)
+			config.Timeout, config.Retries = 20*time.Millisecond, 0
+			// Act
+			_, err := env2js.NewKVSource(context.Background(), config, "AppSettings_")
+			// Assert
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("should return an empty source when no key is under the prefix", func() {
+			// Arrange
+			server, _ := kvFixture(0, 0)
+			defer server.Close()
+			config := kvConfig(server)
+			config.Prefix = "frontend/unknown/"
+			// Act
+			source, err := env2js.NewKVSource(context.Background(), config, "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.List("")).To(BeEmpty())
+		})
+	})
+
+	Describe("Transform", func() {
+		It("should feed the keys into the walker", func() {
+			// Arrange
+			server, _ := kvFixture(0, 0)
+			defer server.Close()
+			options := env2js.Options{
+				Variable:  "AppSettings",
+				KVAddr:    server.URL,
+				KVPrefix:  "frontend/orders/",
+				KVTimeout: time.Second,
+				Environ:   func() []string { return []string{"AppSettings_title=Environment"} },
+				Getenv:    func(key string) string { return map[string]string{env2js.KVTokenEnvKey: "kv-token"}[key] },
+			}
+			// Act
+			output, _, err := env2js.Transform([]byte("const AppSettings = {title: 'App', API: {apiRoot: 'url/server/app'}};"), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("const AppSettings = {title: 'Environment', API: {apiRoot: 'https://example.com/api'}};"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package env2js
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SettingsOutputPathEnvKey string = "SETTINGS_OUTPUT_PATH"
+)
+
+// OutputFilePath returns where the updated settings file is written.
+// Without output directory the settings file is updated in place, otherwise the
+// relative layout of the settings folder is mirrored in the output directory.
+func OutputFilePath(settingsFolderPath string, settingsFilePath string, outputDir string) (string, error) {
+	if outputDir == "" {
+		return settingsFilePath, nil
+	}
+
+	relativePath, err := filepath.Rel(settingsFolderPath, settingsFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(outputDir, relativePath), nil
+}
+
+// CopyAssets copies the untouched files of the settings folder into the output directory.
+// The output directory itself, the pristine copies and the skipped files are ignored.
+func CopyAssets(fsys FileSystem, settingsFolderPath string, outputDir string, skippedFilePaths ...string) error {
+	absoluteOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+
+	skipped := map[string]bool{}
+	for _, skippedFilePath := range skippedFilePaths {
+		skipped[filepath.Clean(skippedFilePath)] = true
+	}
+
+	return walkFiles(fsys, settingsFolderPath, func(path string, entry fs.DirEntry) (bool, error) {
+		if entry.IsDir() {
+			absolutePath, err := filepath.Abs(path)
+			return err == nil && absolutePath == absoluteOutputDir, nil
+		}
+
+		if skipped[filepath.Clean(path)] || strings.HasSuffix(path, OriginalFileSuffix) {
+			return false, nil
+		}
+
+		outputFilePath, err := OutputFilePath(settingsFolderPath, path, outputDir)
+		if err != nil {
+			return false, err
+		}
+
+		return false, CopyFile(fsys, path, outputFilePath)
+	})
+}
+
+// walkFiles visits the entries of the directory tree in lexical order, skipping the directories the visit tells to.
+func walkFiles(fsys FileSystem, dir string, visit func(path string, entry fs.DirEntry) (bool, error)) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		skip, err := visit(path, entry)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && !skip {
+			if err := walkFiles(fsys, path, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CopyFile copies a file, creating the missing parent directories of the destination.
+func CopyFile(fsys FileSystem, sourcePath string, destinationPath string) error {
+	content, err := fsys.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	if err := fsys.MkdirAll(filepath.Dir(destinationPath), fs.ModePerm); err != nil {
+		return err
+	}
+
+	return fsys.WriteFile(destinationPath, content, fs.ModePerm)
+}
@@ -0,0 +1,84 @@
+package env2js_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Output", func() {
+	Describe("OutputFilePath", func() {
+		It("should update the settings file in place without output directory", func() {
+			got, err := env2js.OutputFilePath("dist", "dist/main.js", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal("dist/main.js"))
+		})
+
+		It("should mirror the relative layout in the output directory", func() {
+			got, err := env2js.OutputFilePath("dist", "dist/js/main.js", "out")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.ToSlash(got)).To(Equal("out/js/main.js"))
+		})
+	})
+
+	Describe("CopyAssets", func() {
+		It("should copy the untouched files only", func() {
+			// Arrange
+			folder := GinkgoT().TempDir()
+			outputDir := filepath.Join(folder, "out")
+			Expect(os.MkdirAll(filepath.Join(folder, "assets"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(folder, "index.html"), []byte("<html></html>"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(folder, "assets", "logo.svg"), []byte("<svg/>"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(folder, "main.js"), []byte("const AppSettings = {};"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(folder, "main.js"+env2js.OriginalFileSuffix), []byte("const AppSettings = {};"), 0o644)).To(Succeed())
+			// Act
+			err := env2js.CopyAssets(env2js.OSFileSystem{}, folder, outputDir, filepath.Join(folder, "main.js"))
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(outputDir, "index.html")).To(BeAnExistingFile())
+			Expect(filepath.Join(outputDir, "assets", "logo.svg")).To(BeAnExistingFile())
+			Expect(filepath.Join(outputDir, "main.js")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(outputDir, "main.js"+env2js.OriginalFileSuffix)).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(outputDir, "out")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("Apply", func() {
+		It("should leave the source file untouched in output mode", func() {
+			// Arrange
+			folder := GinkgoT().TempDir()
+			settingsFilePath := filepath.Join(folder, "main.js")
+			Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {MyKey: 'MyValue'};"), 0o644)).To(Succeed())
+			options := env2js.Options{Folder: folder, File: settingsFilePath, Variable: "AppSettings", OutputDir: filepath.Join(folder, "out"),
+				Environ: func() []string { return []string{"AppSettings_MyKey=Test1"} }}
+			// Act
+			report, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.OutputFilePath).To(Equal(filepath.Join(folder, "out", "main.js")))
+			Expect(os.ReadFile(settingsFilePath)).To(BeEquivalentTo("const AppSettings = {MyKey: 'MyValue'};"))
+			Expect(os.ReadFile(report.OutputFilePath)).To(BeEquivalentTo("const AppSettings = {MyKey: 'Test1'};"))
+		})
+
+		It("should lay the output out from the directory of the file without folder", func() {
+			// Arrange
+			folder := GinkgoT().TempDir()
+			settingsFilePath := filepath.Join(folder, "main.js")
+			Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {MyKey: 'MyValue'};"), 0o644)).To(Succeed())
+			options := env2js.Options{File: settingsFilePath, Variable: "AppSettings", OutputDir: filepath.Join(folder, "out"),
+				Environ: func() []string { return []string{"AppSettings_MyKey=Test1"} }}
+			// Act
+			report, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.OutputFilePath).To(Equal(filepath.Join(folder, "out", "main.js")))
+			Expect(os.ReadFile(report.OutputFilePath)).To(BeEquivalentTo("const AppSettings = {MyKey: 'Test1'};"))
+		})
+	})
+})
@@ -0,0 +1,315 @@
+package env2js
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Overrides are the values of the sources targeting the settings variable.
+// Each variable is marked as consumed once the walker applied it to a property, along with its origin.
+type Overrides struct {
+	Prefix   string
+	Sources  Sources
+	consumed map[string]bool
+	origins  map[string]string
+	secrets  map[string]bool
+
+	// Reads the files named by the _FILE variables, the file system of the process when nil
+	FileSystem FileSystem
+	// Decrypts the values written ENC[age,...], see Decrypter
+	Decrypter *Decrypter
+	// Expands the ${NAME} references of the values and of the string literals, see Interpolator
+	Interpolate bool
+	// Transforms of the values by environment variable name, see ReadTransformsFile
+	Transforms map[string]TransformChain
+	// Transforms of the chains by name, the built-in ones when nil
+	Transformers Transformers
+
+	// Errors met while reading the secret files, decrypting, interpolating or transforming the values, see Err
+	errs []error
+}
+
+// UnusedOverride is an environment variable that did not match any property,
+// along with the closest existing keys.
+type UnusedOverride struct {
+	Key         string
+	Suggestions []string
+}
+
+// NewOverrides reads the overrides from the "key=value" environment list.
+// Empty values are ignored, like unset variables.
+func NewOverrides(settingsVariableName string, environ []string) *Overrides {
+	return NewSourceOverrides(settingsVariableName, Sources{NewEnvironSource(EnvironmentSourceName, environ)})
+}
+
+// NewSourceOverrides reads the overrides from the value sources, the first one taking precedence.
+func NewSourceOverrides(settingsVariableName string, sources Sources) *Overrides {
+	return &Overrides{
+		Prefix:   settingsVariableName + "_",
+		Sources:  sources,
+		consumed: map[string]bool{},
+		origins:  map[string]string{},
+		secrets:  map[string]bool{},
+	}
+}
+
+// Key computes the environment variable name of a property path.
+func (o *Overrides) Key(path []string) string {
+	return o.Prefix + strings.Join(path, "_")
+}
+
+// Lookup returns the value overriding the property path and marks it as consumed.
+// Without variable for the path, the value is read from a variant suffixed with transforms,
+// eg : AppSettings_API_apiKey__b64, then from the file named by its _FILE variant.
+// The transforms of the Transforms field run last.
+func (o *Overrides) Lookup(path []string) (string, bool) {
+	if o == nil {
+		return "", false
+	}
+
+	key := o.Key(path)
+	value, ok := o.lookup(key)
+	if !ok || len(o.Transforms[key]) == 0 {
+		return value, ok
+	}
+
+	return o.transform(key, o.Transforms[key], value)
+}
+
+func (o *Overrides) lookup(key string) (string, bool) {
+	if value, source, ok := o.Sources.Find(key); ok {
+		return o.use(key, key, value, source)
+	}
+
+	for _, variable := range o.Sources.List(key + TransformSuffixSeparator) {
+		chain, err := o.Transformers.ParseChain(strings.Split(strings.TrimPrefix(variable, key+TransformSuffixSeparator), TransformSuffixSeparator))
+		if err != nil {
+			// Not a transform suffix, eg : the variable of another property
+			continue
+		}
+		value, source, _ := o.Sources.Find(variable)
+		if value, ok := o.use(key, variable, value, source); ok {
+			return o.transform(key, chain, value)
+		}
+		return "", false
+	}
+
+	secretFilePath, ok := o.Sources.Lookup(key + SecretFileSuffix)
+	if !ok {
+		return "", false
+	}
+	o.consumed[key+SecretFileSuffix] = true
+	value, err := ReadSecretFile(fileSystemOrOS(o.FileSystem), secretFilePath)
+	if err != nil {
+		o.errs = append(o.errs, WrapError(ErrMissingConfig, fmt.Errorf("%s%s : %w", key, SecretFileSuffix, err)))
+		return "", false
+	}
+	o.origins[key] = secretFilePath
+	o.secrets[key] = true
+
+	return o.decrypt(key, value)
+}
+
+// use marks the variable supplying the value of the key as consumed, then decrypts or interpolates the value.
+func (o *Overrides) use(key string, variable string, value string, source ValueSource) (string, bool) {
+	o.consumed[key], o.consumed[variable] = true, true
+	o.origins[key] = source.Name()
+	o.secrets[key] = IsSensitive(source)
+	if IsEncryptedValue(value) {
+		return o.decrypt(key, value)
+	}
+
+	return o.interpolate(key, value)
+}
+
+// transform runs the chain on the value. The errors name the key, never the value.
+func (o *Overrides) transform(key string, chain TransformChain, value string) (string, bool) {
+	transformed, err := o.Transformers.Apply(chain, value)
+	if err != nil {
+		o.errs = append(o.errs, fmt.Errorf("cannot transform %s : %w", key, err))
+		return "", false
+	}
+
+	return transformed, true
+}
+
+// decrypt decrypts the values written ENC[age,...], which are then masked in the logs.
+// The errors name the key, never the value.
+func (o *Overrides) decrypt(key string, value string) (string, bool) {
+	if !IsEncryptedValue(value) {
+		return value, true
+	}
+
+	plaintext, err := o.Decrypter.DecryptValue(value)
+	if err != nil {
+		o.errs = append(o.errs, fmt.Errorf("cannot decrypt %s : %w", key, err))
+		return "", false
+	}
+	o.secrets[key] = true
+
+	return plaintext, true
+}
+
+// interpolate expands the ${NAME} references of the value with the variables of the sources.
+// The value is masked in the logs as soon as one of the variables is.
+func (o *Overrides) interpolate(key string, value string) (string, bool) {
+	if !o.Interpolate {
+		return value, true
+	}
+
+	interpolator := &Interpolator{Lookup: func(name string) (string, bool) {
+		value, source, ok := o.Sources.Find(name)
+		if !ok {
+			return "", false
+		}
+		value, ok = o.decrypt(name, value)
+		if IsSensitive(source) || o.secrets[name] {
+			o.secrets[key] = true
+		}
+		return value, ok
+	}}
+	expanded, err := interpolator.ExpandVariable(key, value)
+	if err != nil {
+		o.errs = append(o.errs, fmt.Errorf("cannot interpolate %s : %w", key, err))
+		return "", false
+	}
+
+	return expanded, true
+}
+
+// Expand interpolates a string literal of the settings file that no variable overrides,
+// eg : 'https://${HOST}/api'. Returns false when the literal is left unchanged.
+func (o *Overrides) Expand(key string, literal string) (string, bool) {
+	if o == nil || !o.Interpolate || !strings.Contains(literal, "$") {
+		return "", false
+	}
+
+	expanded, ok := o.interpolate(key, literal)
+	if !ok || expanded == literal {
+		return "", false
+	}
+	o.origins[key] = InterpolationSourceName
+
+	return expanded, true
+}
+
+// Secret tells whether the value of the environment variable comes from a secret file or a sensitive source.
+func (o *Overrides) Secret(key string) bool {
+	return o != nil && o.secrets[key]
+}
+
+// Origin returns the name of the source, or the secret file, that supplied the value of the environment variable.
+func (o *Overrides) Origin(key string) string {
+	if o == nil {
+		return ""
+	}
+
+	return o.origins[key]
+}
+
+// Consumed tells whether the environment variable, or one of its variants, was applied to a property.
+func (o *Overrides) Consumed(key string) bool {
+	return o != nil && (o.consumed[key] || o.consumed[key+SecretFileSuffix])
+}
+
+// Err returns the errors met while reading the secret files, decrypting, interpolating or transforming the values.
+// The missing secret files, age identities and required variables are ErrMissingConfig, the other errors ErrValidation.
+func (o *Overrides) Err() error {
+	if o == nil {
+		return nil
+	}
+
+	errs := make([]error, len(o.errs))
+	for i, err := range o.errs {
+		errs[i] = wrapErrorKind(ErrValidation, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Unused lists the environment variables that were never consumed, sorted by name.
+func (o *Overrides) Unused() []string {
+	unused := []string{}
+	if o == nil {
+		return unused
+	}
+
+	for _, key := range o.Sources.List(o.Prefix) {
+		if !o.consumed[key] {
+			unused = append(unused, key)
+		}
+	}
+
+	return unused
+}
+
+// UnusedOverrides pairs every unused environment variable with the closest known keys.
+func (o *Overrides) UnusedOverrides(knownKeys []string) []UnusedOverride {
+	// The prefix is shared by every key : it is left out of the distance computation
+	knownPaths := []string{}
+	for _, knownKey := range knownKeys {
+		knownPaths = append(knownPaths, strings.TrimPrefix(knownKey, o.Prefix))
+	}
+
+	unusedOverrides := []UnusedOverride{}
+	for _, key := range o.Unused() {
+		suggestions := []string{}
+		for _, suggestion := range Suggest(strings.TrimPrefix(key, o.Prefix), knownPaths) {
+			suggestions = append(suggestions, o.Prefix+suggestion)
+		}
+		unusedOverrides = append(unusedOverrides, UnusedOverride{Key: key, Suggestions: suggestions})
+	}
+
+	return unusedOverrides
+}
+
+// Suggest returns the candidates with the smallest edit distance to the key.
+// Candidates too far from the key to be a typo are left out.
+func Suggest(key string, candidates []string) []string {
+	maxDistance := len(key) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	suggestions := []string{}
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := EditDistance(strings.ToLower(key), strings.ToLower(candidate))
+		if distance > maxDistance {
+			continue
+		}
+		if distance < bestDistance {
+			bestDistance = distance
+			suggestions = []string{candidate}
+		} else if distance == bestDistance && !slices.Contains(suggestions, candidate) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	return suggestions
+}
+
+// EditDistance computes the Levenshtein distance between two strings.
+func EditDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
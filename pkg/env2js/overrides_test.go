@@ -0,0 +1,91 @@
+package env2js_test
+
+import (
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Overrides", func() {
+	Describe("NewOverrides", func() {
+		It("should only keep the non empty variables with the settings prefix", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_MyKey=Test1", "AppSettings_Empty=", "PATH=/usr/bin", "AppSettings2_MyKey=Test2"})
+			// Act
+			value, ok := overrides.Lookup([]string{"MyKey"})
+			_, okEmpty := overrides.Lookup([]string{"Empty"})
+			// Assert
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("Test1"))
+			Expect(okEmpty).To(BeFalse())
+			Expect(overrides.Unused()).To(BeEmpty())
+		})
+
+		It("should keep the equal signs of the value", func() {
+			value, ok := env2js.NewOverrides("AppSettings", []string{"AppSettings_Query=a=b"}).Lookup([]string{"Query"})
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("a=b"))
+		})
+	})
+
+	Describe("UnusedOverrides", func() {
+		It("should list the unconsumed variables with the closest existing keys", func() {
+			// Arrange
+			input := parse.NewInputString("const AppSettings = {API: {apiRoot: 'url', apiVersion: 1}, MyKey: 'MyValue'};")
+			ast, _ := js.Parse(input, js.Options{})
+			walker := &env2js.Walker{SettingVariableName: "AppSettings", Overrides: env2js.NewOverrides("AppSettings", []string{"AppSettings_API_apiRot=url2", "AppSettings_MyKey=Test1", "AppSettings_Unrelated=Test2"})}
+			// Act
+			js.Walk(walker, ast)
+			// Assert
+			Expect(walker.Keys()).To(Equal([]string{"AppSettings_API_apiRoot", "AppSettings_API_apiVersion", "AppSettings_MyKey"}))
+			Expect(walker.Overrides.UnusedOverrides(walker.Keys())).To(Equal([]env2js.UnusedOverride{
+				{Key: "AppSettings_API_apiRot", Suggestions: []string{"AppSettings_API_apiRoot"}},
+				{Key: "AppSettings_Unrelated", Suggestions: []string{}},
+			}))
+		})
+	})
+
+	Describe("LogUnusedOverrides", func() {
+		It("should warn about the unused variables with a did-you-mean suggestion", func() {
+			// Arrange
+			logs := new(LogRecorder)
+			walker := &env2js.Walker{
+				SettingVariableName: "AppSettings",
+				Overrides:           env2js.NewOverrides("AppSettings", []string{"AppSettings_API_apiRot=url2", "AppSettings_Unrelated=Test2"}),
+				Settings:            []env2js.Setting{{Key: "AppSettings_API_apiRoot"}},
+			}
+			// Act
+			env2js.LogUnusedOverrides(slog.New(logs), walker.Overrides.UnusedOverrides(walker.Keys()))
+			// Assert
+			Expect(logs.Warnings).To(Equal([]string{
+				"⚠ Unused environment variable : AppSettings_API_apiRot (did you mean AppSettings_API_apiRoot ?)",
+				"⚠ Unused environment variable : AppSettings_Unrelated",
+			}))
+		})
+	})
+
+	Describe("Suggest", func() {
+		It("should suggest the closest candidates", func() {
+			Expect(env2js.Suggest("API_apiRot", []string{"API_apiRoot", "API_apiVersion", "MyKey"})).To(Equal([]string{"API_apiRoot"}))
+		})
+
+		It("should not suggest the candidates too far from the key", func() {
+			Expect(env2js.Suggest("zzz", []string{"a", "b", "c_d"})).To(BeEmpty())
+		})
+	})
+
+	Describe("EditDistance", func() {
+		It("should count the insertions, deletions and substitutions", func() {
+			Expect(env2js.EditDistance("apiRoot", "apiRoot")).To(Equal(0))
+			Expect(env2js.EditDistance("apiRot", "apiRoot")).To(Equal(1))
+			Expect(env2js.EditDistance("kitten", "sitting")).To(Equal(3))
+			Expect(env2js.EditDistance("", "abc")).To(Equal(3))
+		})
+	})
+})
@@ -0,0 +1,216 @@
+package env2js
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tdewolff/parse/v2/js"
+)
+
+const (
+	SchemaFormatJSON       string = "json"
+	SchemaFormatTypeScript string = "ts"
+
+	JSONSchemaVersion string = "https://json-schema.org/draft/2020-12/schema"
+)
+
+var SchemaFormats = []string{SchemaFormatJSON, SchemaFormatTypeScript}
+
+// Schema is the subset of JSON Schema describing a settings object.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Default     any                `json:"default,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+
+	// Validation keywords, see ValidateSettings
+	AdditionalProperties *bool    `json:"additionalProperties,omitempty"`
+	Enum                 []any    `json:"enum,omitempty"`
+	Minimum              *float64 `json:"minimum,omitempty"`
+	Maximum              *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64 `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int     `json:"minLength,omitempty"`
+	MaxLength            *int     `json:"maxLength,omitempty"`
+	Pattern              string   `json:"pattern,omitempty"`
+	Format               string   `json:"format,omitempty"`
+
+	// Properties in order of appearance in the settings object
+	PropertyNames []string `json:"-"`
+}
+
+// SettingsObject returns the object literal assigned to the settings variable, or nil.
+func SettingsObject(ast *js.AST, settingsVariableName string) *js.ObjectExpr {
+	finder := &settingsObjectFinder{SettingVariableName: settingsVariableName}
+	js.Walk(finder, ast)
+	return finder.Object
+}
+
+type settingsObjectFinder struct {
+	SettingVariableName string
+	Object              *js.ObjectExpr
+}
+
+func (f *settingsObjectFinder) Enter(n js.INode) js.IVisitor {
+	if f.Object != nil {
+		return nil
+	}
+	if n, ok := n.(*js.BindingElement); ok && n.Binding != nil && n.Binding.String() == f.SettingVariableName {
+		if object, ok := n.Default.(*js.ObjectExpr); ok {
+			f.Object = object
+			return nil
+		}
+	}
+	return f
+}
+
+func (f *settingsObjectFinder) Exit(n js.INode) {}
+
+// InferSchema infers the schema of the settings object from its literal : the types, the nested objects,
+// the arrays and the default values. Every property of the literal is required.
+// The comments written above the properties become their description.
+func InferSchema(object *js.ObjectExpr, title string, comments []PropertyComment) *Schema {
+	schema := inferSchema(object, []string{}, comments)
+	schema.Schema = JSONSchemaVersion
+	schema.Title = title
+	return schema
+}
+
+func inferSchema(expr js.IExpr, path []string, comments []PropertyComment) *Schema {
+	schema := &Schema{}
+	if index := slices.IndexFunc(comments, func(comment PropertyComment) bool { return slices.Equal(comment.Path, path) }); index >= 0 && len(path) > 0 {
+		schema.Description = comments[index].Text
+	}
+
+	switch expr := expr.(type) {
+	case *js.LiteralExpr:
+		schema.Type = LiteralType(expr)
+		switch schema.Type {
+		case "string":
+			schema.Default = LiteralValue(expr)
+		case "boolean":
+			schema.Default = expr.TokenType == js.TrueToken
+		case "number":
+			if number, err := strconv.ParseFloat(string(expr.Data), 64); err == nil {
+				schema.Default = number
+			}
+		}
+	case *js.UnaryExpr:
+		// Minified booleans : !0 and !1
+		if literal, ok := expr.X.(*js.LiteralExpr); ok && expr.Op == js.NotToken && literal.TokenType == js.IntegerToken {
+			schema.Type = "boolean"
+			schema.Default = string(literal.Data) == "0"
+		} else if ok && expr.Op == js.NegToken && js.IsNumeric(literal.TokenType) {
+			schema.Type = "number"
+			if number, err := strconv.ParseFloat(string(literal.Data), 64); err == nil {
+				schema.Default = -number
+			}
+		}
+	case *js.ObjectExpr:
+		schema.Type = "object"
+		schema.Properties = map[string]*Schema{}
+		schema.Required = []string{}
+		for _, property := range expr.List {
+			if property.Spread || property.Name == nil || property.Name.IsComputed() {
+				continue
+			}
+			name := PropertyNameValue(property.Name)
+			schema.Properties[name] = inferSchema(property.Value, append(slices.Clone(path), property.Name.String()), comments)
+			schema.PropertyNames = append(schema.PropertyNames, name)
+			schema.Required = append(schema.Required, name)
+		}
+	case *js.ArrayExpr:
+		schema.Type = "array"
+		// The items share a schema only when all of them have the same type
+		for i, item := range expr.List {
+			if item.Value == nil || item.Spread {
+				schema.Items = nil
+				break
+			}
+			itemSchema := inferSchema(item.Value, append(slices.Clone(path), "["+strconv.Itoa(i)+"]"), comments)
+			itemSchema.Default, itemSchema.Description = nil, ""
+			if i > 0 && (schema.Items == nil || schema.Items.Type != itemSchema.Type || itemSchema.Type == "") {
+				schema.Items = nil
+				break
+			}
+			schema.Items = itemSchema
+		}
+	}
+
+	return schema
+}
+
+// PropertyNameValue returns the name of the property, without the quotes of string names.
+func PropertyNameValue(name *js.PropertyName) string {
+	if name.Literal.TokenType == js.StringToken {
+		return LiteralValue(&name.Literal)
+	}
+
+	return string(name.Literal.Data)
+}
+
+// FormatSchema writes the schema either as a JSON Schema or as a TypeScript declaration.
+func FormatSchema(w io.Writer, format string, schema *Schema) error {
+	switch format {
+	case SchemaFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(schema)
+	case SchemaFormatTypeScript:
+		fmt.Fprintf(w, "export interface %s %s\n", schema.Title, typeScriptType(schema, 0))
+		return nil
+	}
+
+	return fmt.Errorf("unknown schema format %q, expected one of : %s", format, strings.Join(SchemaFormats, ", "))
+}
+
+func typeScriptType(schema *Schema, depth int) string {
+	switch schema.Type {
+	case "string", "boolean", "number", "null":
+		return schema.Type
+	case "array":
+		if schema.Items == nil {
+			return "unknown[]"
+		}
+		itemType := typeScriptType(schema.Items, depth)
+		if schema.Items.Type == "object" {
+			return "Array<" + itemType + ">"
+		}
+		return itemType + "[]"
+	case "object":
+		indent := strings.Repeat("  ", depth+1)
+		builder := strings.Builder{}
+		builder.WriteString("{\n")
+		for _, name := range schema.PropertyNames {
+			property := schema.Properties[name]
+			if property.Description != "" {
+				builder.WriteString(indent + "/** " + strings.ReplaceAll(property.Description, "\n", " ") + " */\n")
+			}
+			optional := ""
+			if !slices.Contains(schema.Required, name) {
+				optional = "?"
+			}
+			builder.WriteString(indent + typeScriptPropertyName(name) + optional + ": " + typeScriptType(property, depth+1) + ";\n")
+		}
+		builder.WriteString(strings.Repeat("  ", depth) + "}")
+		return builder.String()
+	}
+
+	return "unknown"
+}
+
+func typeScriptPropertyName(name string) string {
+	if js.AsIdentifierName([]byte(name)) {
+		return name
+	}
+
+	return strconv.Quote(name)
+}
@@ -0,0 +1,91 @@
+package env2js_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Schema", func() {
+	const jsString = `const AppSettings = {
+  /** Whether the app is served */
+  isServed: !0,
+  API: {apiRoot: 'url/server/app', timeout: 30, 'retry-count': -1},
+  MyArray: ['MyValue1', 'MyValue2'],
+  Mixed: [1, 'a'],
+  Users: [{name: 'a'}],
+  Nothing: null,
+};`
+	var schema *env2js.Schema
+	BeforeEach(func() {
+		ast, err := js.Parse(parse.NewInputString(jsString), js.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		object := env2js.SettingsObject(ast, "AppSettings")
+		Expect(object).NotTo(BeNil())
+		schema = env2js.InferSchema(object, "AppSettings", env2js.PropertyComments([]byte(jsString), "AppSettings"))
+	})
+
+	Describe("SettingsObject", func() {
+		It("should return nil when the settings variable is not found", func() {
+			ast, _ := js.Parse(parse.NewInputString("const Other = {};"), js.Options{})
+			Expect(env2js.SettingsObject(ast, "AppSettings")).To(BeNil())
+		})
+	})
+
+	Describe("InferSchema", func() {
+		It("should infer the types, the defaults and the required keys", func() {
+			Expect(schema.Type).To(Equal("object"))
+			Expect(schema.Required).To(Equal([]string{"isServed", "API", "MyArray", "Mixed", "Users", "Nothing"}))
+			Expect(schema.Properties["isServed"]).To(Equal(&env2js.Schema{Type: "boolean", Default: true, Description: "Whether the app is served"}))
+			Expect(schema.Properties["API"].Properties["apiRoot"]).To(Equal(&env2js.Schema{Type: "string", Default: "url/server/app"}))
+			Expect(schema.Properties["API"].Properties["retry-count"]).To(Equal(&env2js.Schema{Type: "number", Default: -1.0}))
+			Expect(schema.Properties["MyArray"].Items).To(Equal(&env2js.Schema{Type: "string"}))
+			Expect(schema.Properties["Mixed"].Items).To(BeNil())
+			Expect(schema.Properties["Users"].Items.Type).To(Equal("object"))
+			Expect(schema.Properties["Nothing"].Type).To(Equal("null"))
+		})
+	})
+
+	Describe("FormatSchema", func() {
+		var buffer *bytes.Buffer
+		BeforeEach(func() {
+			buffer = new(bytes.Buffer)
+		})
+
+		It("should write a JSON Schema", func() {
+			Expect(env2js.FormatSchema(buffer, env2js.SchemaFormatJSON, schema)).To(Succeed())
+			Expect(buffer.String()).To(HavePrefix("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"title\": \"AppSettings\",\n  \"type\": \"object\",\n"))
+			Expect(buffer.String()).To(ContainSubstring("\"timeout\": {\n          \"type\": \"number\",\n          \"default\": 30\n        }"))
+		})
+
+		It("should write a TypeScript declaration", func() {
+			Expect(env2js.FormatSchema(buffer, env2js.SchemaFormatTypeScript, schema)).To(Succeed())
+			Expect(buffer.String()).To(Equal(`export interface AppSettings {
+  /** Whether the app is served */
+  isServed: boolean;
+  API: {
+    apiRoot: string;
+    timeout: number;
+    "retry-count": number;
+  };
+  MyArray: string[];
+  Mixed: unknown[];
+  Users: Array<{
+    name: string;
+  }>;
+  Nothing: null;
+}
+`))
+		})
+
+		It("should fail with an unknown format", func() {
+			Expect(env2js.FormatSchema(buffer, "xsd", schema)).To(MatchError("unknown schema format \"xsd\", expected one of : json, ts"))
+		})
+	})
+})
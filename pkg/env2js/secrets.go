@@ -0,0 +1,48 @@
+package env2js
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SettingsSecretsDirsEnvKey string = "SETTINGS_SECRETS_DIRS"
+
+	// Suffix of the environment variables holding the path of the file to read the value from,
+	// eg : AppSettings_apiKey_FILE=/run/secrets/api-key
+	SecretFileSuffix string = "_FILE"
+)
+
+// ReadSecretFile reads the value of a secret file, without the trailing newlines.
+func ReadSecretFile(fsys FileSystem, secretFilePath string) (string, error) {
+	secretBytes, err := fsys.ReadFile(secretFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(secretBytes), "\r\n"), nil
+}
+
+// ReadSecretsDir reads a mounted directory where each file name is an environment variable name,
+// like the Kubernetes ConfigMap and Secret volumes. Returns a "key=value" list.
+// The hidden files and the sub-directories are ignored, eg : the ..data link of Kubernetes.
+func ReadSecretsDir(fsys FileSystem, secretsDirPath string) ([]string, error) {
+	entries, err := fsys.ReadDir(secretsDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	environ := []string{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		value, err := ReadSecretFile(fsys, filepath.Join(secretsDirPath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		environ = append(environ, entry.Name()+"="+value)
+	}
+
+	return environ, nil
+}
@@ -0,0 +1,90 @@
+package env2js_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Secrets", func() {
+	var folder string
+	BeforeEach(func() {
+		folder = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(folder, "api-key"), []byte("s3cr3t\n\n"), 0o644)).To(Succeed())
+	})
+
+	Describe("ReadSecretFile", func() {
+		It("should trim the trailing newlines", func() {
+			Expect(env2js.ReadSecretFile(env2js.OSFileSystem{}, filepath.Join(folder, "api-key"))).To(Equal("s3cr3t"))
+		})
+	})
+
+	Describe("ReadSecretsDir", func() {
+		It("should read every file named after an environment variable", func() {
+			// Arrange
+			secretsDir := filepath.Join(folder, "secrets")
+			Expect(os.MkdirAll(filepath.Join(secretsDir, "..data"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(secretsDir, "AppSettings_apiKey"), []byte("s3cr3t\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(secretsDir, ".hidden"), []byte("ignored"), 0o644)).To(Succeed())
+			// Act
+			environ, err := env2js.ReadSecretsDir(env2js.OSFileSystem{}, secretsDir)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(environ).To(Equal([]string{"AppSettings_apiKey=s3cr3t"}))
+		})
+	})
+
+	Describe("NewDirSource", func() {
+		It("should mask the values of the secrets directory", func() {
+			// Arrange
+			secretsDir := filepath.Join(folder, "secrets")
+			Expect(os.MkdirAll(secretsDir, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(secretsDir, "AppSettings_apiKey"), []byte("s3cr3t\n"), 0o644)).To(Succeed())
+			// Act
+			source, err := env2js.NewDirSource(env2js.OSFileSystem{}, secretsDir)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.Sensitive()).To(BeTrue())
+			Expect(env2js.IsSensitive(source)).To(BeTrue())
+		})
+	})
+
+	Describe("Overrides", func() {
+		It("should read the value from the file of the _FILE variant", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_apiKey_FILE=" + filepath.Join(folder, "api-key")})
+			// Act
+			value, ok := overrides.Lookup([]string{"apiKey"})
+			// Assert
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("s3cr3t"))
+			Expect(overrides.Consumed("AppSettings_apiKey")).To(BeTrue())
+			Expect(overrides.Unused()).To(BeEmpty())
+		})
+
+		It("should prefer the variable over its _FILE variant", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_apiKey=plain", "AppSettings_apiKey_FILE=" + filepath.Join(folder, "api-key")})
+			// Act
+			value, _ := overrides.Lookup([]string{"apiKey"})
+			// Assert
+			Expect(value).To(Equal("plain"))
+			Expect(overrides.Unused()).To(Equal([]string{"AppSettings_apiKey_FILE"}))
+		})
+
+		It("should report the unreadable files", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_apiKey_FILE=" + filepath.Join(folder, "missing")})
+			// Act
+			_, ok := overrides.Lookup([]string{"apiKey"})
+			// Assert
+			Expect(ok).To(BeFalse())
+			Expect(overrides.Err()).To(MatchError(ContainSubstring("AppSettings_apiKey_FILE : open ")))
+		})
+	})
+})
@@ -0,0 +1,241 @@
+package env2js
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const (
+	SettingsPrecedenceEnvKey string = "SETTINGS_PRECEDENCE"
+
+	// Kinds of value sources, see -precedence
+	SetSourceKind        string = "set"
+	EnvSourceKind        string = "env"
+	VaultSourceKind      string = "vault"
+	KVSourceKind         string = "kv"
+	SecretsDirSourceKind string = "secrets-dir"
+	EnvFileSourceKind    string = "env-file"
+
+	EnvironmentSourceName string = "environment"
+	SetSourceName         string = "-set"
+)
+
+// Highest precedence first
+var DefaultPrecedence = StringList{SetSourceKind, EnvSourceKind, VaultSourceKind, KVSourceKind, SecretsDirSourceKind, EnvFileSourceKind}
+
+// ValueSource supplies the values of the overrides by environment variable name.
+type ValueSource interface {
+	// Name identifies the source in the logs, eg : environment or /etc/env2js/.env
+	Name() string
+	// Lookup returns the value of the key
+	Lookup(key string) (string, bool)
+	// List returns the keys starting with the prefix
+	List(prefix string) []string
+}
+
+// SensitiveSource is implemented by the value sources holding secrets : their values are masked in the logs.
+type SensitiveSource interface {
+	Sensitive() bool
+}
+
+// MapSource is a value source holding its values in memory.
+type MapSource struct {
+	name      string
+	values    map[string]string
+	sensitive bool
+}
+
+// NewEnvironSource builds a value source from a "key=value" list, a later entry overriding an earlier one.
+// Empty values are ignored, like unset variables.
+func NewEnvironSource(name string, environ []string) *MapSource {
+	source := &MapSource{name: name, values: map[string]string{}}
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" || value == "" {
+			continue
+		}
+		source.values[key] = value
+	}
+
+	return source
+}
+
+// NewDotenvSource reads a .env file, see ParseDotenv. An age encrypted file is decrypted first, its values are then sensitive.
+func NewDotenvSource(fsys FileSystem, envFilePath string, decrypter *Decrypter) (*MapSource, error) {
+	envBytes, err := fsys.ReadFile(envFilePath)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := IsEncryptedFile(envBytes)
+	if encrypted {
+		if envBytes, err = decrypter.DecryptFile(envBytes); err != nil {
+			return nil, fmt.Errorf("cannot decrypt env file %s : %w", envFilePath, err)
+		}
+	}
+	entries, err := ParseDotenv(string(envBytes))
+	if err != nil {
+		return nil, fmt.Errorf("invalid env file %s : %w", envFilePath, err)
+	}
+
+	source := NewEnvironSource(envFilePath, entries)
+	source.sensitive = encrypted
+
+	return source, nil
+}
+
+// NewDirSource reads a secrets directory, see ReadSecretsDir. Its values are masked in the logs, like the _FILE variants.
+func NewDirSource(fsys FileSystem, secretsDirPath string) (*MapSource, error) {
+	entries, err := ReadSecretsDir(fsys, secretsDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	source := NewEnvironSource(secretsDirPath, entries)
+	source.sensitive = true
+
+	return source, nil
+}
+
+func (s *MapSource) Name() string {
+	return s.name
+}
+
+func (s *MapSource) Sensitive() bool {
+	return s.sensitive
+}
+
+func (s *MapSource) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *MapSource) List(prefix string) []string {
+	keys := []string{}
+	for key := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Sources layers value sources, the first one taking precedence over the following ones.
+type Sources []ValueSource
+
+func (s Sources) Name() string {
+	names := []string{}
+	for _, source := range s {
+		names = append(names, source.Name())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (s Sources) Lookup(key string) (string, bool) {
+	value, _, ok := s.Find(key)
+	return value, ok
+}
+
+// Find returns the value of the key along with the source supplying it.
+func (s Sources) Find(key string) (string, ValueSource, bool) {
+	for _, source := range s {
+		if value, ok := source.Lookup(key); ok {
+			return value, source, true
+		}
+	}
+
+	return "", nil, false
+}
+
+func (s Sources) List(prefix string) []string {
+	keys := []string{}
+	for _, source := range s {
+		for _, key := range source.List(prefix) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// ValueSources builds the value sources in the order of the precedence option, unless the sources option is set.
+// The .env files and the secrets directories given last take precedence over the previous ones.
+func ValueSources(ctx context.Context, options Options, decrypter *Decrypter) (Sources, error) {
+	if options.Sources != nil {
+		return options.Sources, nil
+	}
+	options = options.withDefaults()
+	precedence := options.Precedence
+	if len(precedence) == 0 {
+		precedence = DefaultPrecedence
+	}
+
+	sources := Sources{}
+	for _, kind := range precedence {
+		switch kind {
+		case SetSourceKind:
+			sources = append(sources, NewEnvironSource(SetSourceName, options.Sets))
+		case EnvSourceKind:
+			sources = append(sources, NewEnvironSource(EnvironmentSourceName, options.Environ()))
+		case VaultSourceKind:
+			if options.VaultPath == "" {
+				continue
+			}
+			source, err := NewVaultSource(ctx, NewVaultConfig(options), options.Variable+"_")
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, source)
+		case KVSourceKind:
+			if options.KVAddr == "" {
+				continue
+			}
+			source, err := NewKVSource(ctx, NewKVConfig(options), options.Variable+"_")
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, source)
+		case SecretsDirSourceKind:
+			for i := len(options.SecretsDirs) - 1; i >= 0; i-- {
+				source, err := NewDirSource(options.FileSystem, options.SecretsDirs[i])
+				if err != nil {
+					return nil, wrapErrorKind(ErrMissingConfig, err)
+				}
+				sources = append(sources, source)
+			}
+		case EnvFileSourceKind:
+			for i := len(options.EnvFiles) - 1; i >= 0; i-- {
+				source, err := NewDotenvSource(options.FileSystem, options.EnvFiles[i], decrypter)
+				if err != nil {
+					return nil, wrapErrorKind(ErrMissingConfig, err)
+				}
+				sources = append(sources, source)
+			}
+		default:
+			return nil, WrapError(ErrUsage, fmt.Errorf("unknown value source %q, expected one of : %s", kind, DefaultPrecedence.String()))
+		}
+	}
+
+	return sources, nil
+}
+
+// LogOrigins logs the source of every applied value, eg : AppSettings_API_apiRoot from /etc/env2js/.env
+func LogOrigins(logger *slog.Logger, changes []Change) {
+	for _, change := range changes {
+		logger.Info("↳ " + change.Key + " from " + change.Origin)
+	}
+}
+
+// IsSensitive tells whether the values of the source are masked in the logs.
+func IsSensitive(source ValueSource) bool {
+	sensitiveSource, ok := source.(SensitiveSource)
+	return ok && sensitiveSource.Sensitive()
+}
@@ -0,0 +1,103 @@
+package env2js_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Sources", func() {
+	var folder string
+	var options env2js.Options
+	BeforeEach(func() {
+		folder = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(folder, ".env"), []byte("AppSettings_A=file\nAppSettings_B=file\nAppSettings_C=file\nAppSettings_D=file"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(folder, ".env.local"), []byte("AppSettings_B=local\nAppSettings_C=local\nAppSettings_D=local"), 0o644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(folder, "secrets"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(folder, "secrets", "AppSettings_C"), []byte("secret\n"), 0o644)).To(Succeed())
+		options = env2js.Options{
+			EnvFiles:    env2js.StringList{filepath.Join(folder, ".env"), filepath.Join(folder, ".env.local")},
+			SecretsDirs: env2js.StringList{filepath.Join(folder, "secrets")},
+			Sets:        env2js.StringList{"AppSettings_E=set"},
+			Environ:     func() []string { return []string{"AppSettings_D=environment", "AppSettings_E=environment"} },
+		}
+	})
+
+	// lookup returns the value of every key along with the name of its source
+	lookup := func(overrides *env2js.Overrides, paths ...string) map[string]string {
+		values := map[string]string{}
+		for _, path := range paths {
+			value, ok := overrides.Lookup([]string{path})
+			Expect(ok).To(BeTrue())
+			values[path] = value + " from " + overrides.Origin(overrides.Key([]string{path}))
+		}
+		return values
+	}
+
+	Describe("ValueSources", func() {
+		It("should layer the sources in the default precedence", func() {
+			// Act
+			sources, err := env2js.ValueSources(context.Background(), options, nil)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lookup(env2js.NewSourceOverrides("AppSettings", sources), "A", "B", "C", "D", "E")).To(Equal(map[string]string{
+				"A": "file from " + filepath.Join(folder, ".env"),
+				"B": "local from " + filepath.Join(folder, ".env.local"),
+				"C": "secret from " + filepath.Join(folder, "secrets"),
+				"D": "environment from environment",
+				"E": "set from -set",
+			}))
+		})
+
+		It("should follow the configured precedence and leave out the missing kinds", func() {
+			// Arrange
+			options.Precedence = env2js.StringList{env2js.EnvFileSourceKind, env2js.EnvSourceKind}
+			// Act
+			sources, err := env2js.ValueSources(context.Background(), options, nil)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lookup(env2js.NewSourceOverrides("AppSettings", sources), "C", "D", "E")).To(Equal(map[string]string{
+				"C": "local from " + filepath.Join(folder, ".env.local"),
+				"D": "local from " + filepath.Join(folder, ".env.local"),
+				"E": "environment from environment",
+			}))
+		})
+
+		It("should fail on unknown kinds and invalid env files", func() {
+			_, err := env2js.ValueSources(context.Background(), env2js.Options{Precedence: env2js.StringList{"ldap"}}, nil)
+			Expect(err).To(MatchError(`unknown value source "ldap", expected one of : set,env,vault,kv,secrets-dir,env-file`))
+
+			envFilePath := filepath.Join(folder, ".env.invalid")
+			Expect(os.WriteFile(envFilePath, []byte("A"), 0o644)).To(Succeed())
+			_, err = env2js.ValueSources(context.Background(), env2js.Options{EnvFiles: env2js.StringList{envFilePath}}, nil)
+			Expect(err).To(MatchError("invalid env file " + envFilePath + " : line 1 : missing = after A"))
+		})
+	})
+
+	Describe("Sources", func() {
+		It("should list the keys of every source once", func() {
+			sources := env2js.Sources{env2js.NewEnvironSource("a", []string{"P_B=1", "P_A=1", "Q_A=1"}), env2js.NewEnvironSource("b", []string{"P_A=2", "P_C=2"})}
+			Expect(sources.List("P_")).To(Equal([]string{"P_A", "P_B", "P_C"}))
+			Expect(sources.Name()).To(Equal("a, b"))
+		})
+	})
+
+	Describe("LogOrigins", func() {
+		It("should log the source of every applied value", func() {
+			// Arrange
+			logs := new(LogRecorder)
+			changes := []env2js.Change{{Key: "AppSettings_API_apiRoot", Origin: "/etc/env2js/.env"}}
+			// Act
+			env2js.LogOrigins(slog.New(logs), changes)
+			// Assert
+			Expect(logs.Infos).To(Equal([]string{"↳ AppSettings_API_apiRoot from /etc/env2js/.env"}))
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package env2js
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	SettingsRequiredKeysEnvKey string = "SETTINGS_REQUIRED_KEYS"
+)
+
+// StringList is a repeatable command-line flag, eg : -require KeyA -require KeyB
+type StringList []string
+
+func (l *StringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *StringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// SplitList splits a comma separated environment variable value.
+func SplitList(value string) StringList {
+	list := StringList{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// CheckStrict lists every reason for the strict mode to fail :
+// an environment variable did not match any property, or a required key did not receive an environment value.
+// A missing settings variable always fails, see VariableNotFoundError.
+func CheckStrict(w *Walker, requiredKeys []string) error {
+	problems := []string{}
+	for _, unusedOverride := range w.Overrides.UnusedOverrides(w.Keys()) {
+		problems = append(problems, "unused environment variable "+unusedOverride.Key)
+	}
+
+	for _, requiredKey := range requiredKeys {
+		if !w.Overrides.Consumed(requiredKey) {
+			problems = append(problems, "missing required environment variable "+requiredKey)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New("strict mode : " + strings.Join(problems, ", "))
+}
@@ -0,0 +1,58 @@
+package env2js_test
+
+import (
+	"flag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Strict", func() {
+	walkJSString := func(jsString string, environ ...string) *env2js.Walker {
+		ast, _ := js.Parse(parse.NewInputString(jsString), js.Options{})
+		walker := &env2js.Walker{SettingVariableName: "AppSettings", Overrides: env2js.NewOverrides("AppSettings", environ)}
+		js.Walk(walker, ast)
+		return walker
+	}
+
+	Describe("CheckStrict", func() {
+		It("should succeed when every variable is used and every required key is set", func() {
+			walker := walkJSString("const AppSettings = {MyKey: 'MyValue'};", "AppSettings_MyKey=Test1")
+			Expect(env2js.CheckStrict(walker, []string{"AppSettings_MyKey"})).To(Succeed())
+		})
+
+		It("should fail when a variable does not match any property", func() {
+			walker := walkJSString("const AppSettings = {MyKey: 'MyValue'};", "AppSettings_MyKy=Test1")
+			Expect(env2js.CheckStrict(walker, nil)).To(MatchError("strict mode : unused environment variable AppSettings_MyKy"))
+		})
+
+		It("should fail when a required key did not receive a value", func() {
+			walker := walkJSString("const AppSettings = {MyKey: 'MyValue'};")
+			Expect(env2js.CheckStrict(walker, []string{"AppSettings_MyKey"})).To(MatchError("strict mode : missing required environment variable AppSettings_MyKey"))
+		})
+	})
+
+	Describe("StringList", func() {
+		It("should collect the repeated flag values", func() {
+			// Arrange
+			var list env2js.StringList
+			flags := flag.NewFlagSet("prog", flag.ContinueOnError)
+			flags.Var(&list, "require", "")
+			// Act
+			err := flags.Parse([]string{"-require", "KeyA", "-require", "KeyB"})
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(Equal(env2js.StringList{"KeyA", "KeyB"}))
+			Expect(list.String()).To(Equal("KeyA,KeyB"))
+		})
+
+		It("should split the comma separated values", func() {
+			Expect(env2js.SplitList(" KeyA, KeyB ,,")).To(Equal(env2js.StringList{"KeyA", "KeyB"}))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package env2js
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+)
+
+const (
+	SettingsTemplateDirEnvKey string = "SETTINGS_TEMPLATE_DIR"
+
+	// Suffix appended to the settings file name to store its pristine copy
+	OriginalFileSuffix string = ".env2js-orig"
+)
+
+// OriginalFilePath returns the location of the pristine copy of the settings file.
+// Without template directory the copy sits next to the settings file.
+func OriginalFilePath(settingsFilePath string, templateDir string) string {
+	if templateDir == "" {
+		return settingsFilePath + OriginalFileSuffix
+	}
+
+	return filepath.Join(templateDir, filepath.Base(settingsFilePath)+OriginalFileSuffix)
+}
+
+// ReadOriginalFile returns the pristine content of the settings file, and whether it was saved.
+// On first run the current content is saved as the pristine copy, so that the
+// following runs always start from the build-time defaults.
+func ReadOriginalFile(fsys FileSystem, settingsFilePath string, templateDir string) ([]byte, bool, error) {
+	originalFilePath := OriginalFilePath(settingsFilePath, templateDir)
+	originalBytes, err := fsys.ReadFile(originalFilePath)
+	if err == nil {
+		return originalBytes, false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, err
+	}
+
+	jsBytes, err := fsys.ReadFile(settingsFilePath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if templateDir != "" {
+		if err := fsys.MkdirAll(templateDir, fs.ModePerm); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := fsys.WriteFile(originalFilePath, jsBytes, fs.ModePerm); err != nil {
+		return nil, false, err
+	}
+
+	return jsBytes, true, nil
+}
+
+// PeekOriginalFile returns the pristine content of the settings file, without saving it on first run.
+func PeekOriginalFile(fsys FileSystem, settingsFilePath string, templateDir string) ([]byte, error) {
+	originalBytes, err := fsys.ReadFile(OriginalFilePath(settingsFilePath, templateDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fsys.ReadFile(settingsFilePath)
+	}
+
+	return originalBytes, err
+}
+
+// RestoreOriginalFile puts the pristine copy back in place of the settings file.
+func RestoreOriginalFile(fsys FileSystem, settingsFilePath string, templateDir string) error {
+	originalBytes, err := fsys.ReadFile(OriginalFilePath(settingsFilePath, templateDir))
+	if err != nil {
+		return err
+	}
+
+	return WrapError(ErrWrite, fsys.WriteFile(settingsFilePath, originalBytes, fs.ModePerm))
+}
@@ -0,0 +1,102 @@
+package env2js_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Template", func() {
+	var folder string
+	var settingsFilePath string
+	BeforeEach(func() {
+		folder = GinkgoT().TempDir()
+		settingsFilePath = filepath.Join(folder, "main.js")
+		Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {MyKey: 'MyValue'};"), 0o644)).To(Succeed())
+	})
+
+	Describe("OriginalFilePath", func() {
+		It("should put the pristine copy next to the settings file by default", func() {
+			Expect(env2js.OriginalFilePath("dist/main.js", "")).To(Equal("dist/main.js" + env2js.OriginalFileSuffix))
+		})
+
+		It("should put the pristine copy in the template directory when given", func() {
+			Expect(filepath.ToSlash(env2js.OriginalFilePath("dist/main.js", "templates"))).To(Equal("templates/main.js" + env2js.OriginalFileSuffix))
+		})
+	})
+
+	Describe("ReadOriginalFile", func() {
+		It("should save the pristine copy on first run", func() {
+			// Act
+			jsBytes, saved, err := env2js.ReadOriginalFile(env2js.OSFileSystem{}, settingsFilePath, "")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeTrue())
+			Expect(string(jsBytes)).To(Equal("const AppSettings = {MyKey: 'MyValue'};"))
+			Expect(settingsFilePath + env2js.OriginalFileSuffix).To(BeAnExistingFile())
+		})
+
+		It("should read the pristine copy instead of the already updated file", func() {
+			// Arrange
+			templateDir := filepath.Join(folder, "templates")
+			_, _, err := env2js.ReadOriginalFile(env2js.OSFileSystem{}, settingsFilePath, templateDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {MyKey: 'Test1'};"), 0o644)).To(Succeed())
+			// Act
+			jsBytes, saved, err := env2js.ReadOriginalFile(env2js.OSFileSystem{}, settingsFilePath, templateDir)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeFalse())
+			Expect(string(jsBytes)).To(Equal("const AppSettings = {MyKey: 'MyValue'};"))
+		})
+	})
+
+	Describe("RestoreOriginalFile", func() {
+		It("should put the pristine copy back", func() {
+			// Arrange
+			_, _, err := env2js.ReadOriginalFile(env2js.OSFileSystem{}, settingsFilePath, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(settingsFilePath, []byte("const AppSettings = {MyKey: 'Test1'};"), 0o644)).To(Succeed())
+			// Act
+			err = env2js.RestoreOriginalFile(env2js.OSFileSystem{}, settingsFilePath, "")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadFile(settingsFilePath)).To(BeEquivalentTo("const AppSettings = {MyKey: 'MyValue'};"))
+		})
+
+		It("should return an error when there is no pristine copy", func() {
+			Expect(env2js.RestoreOriginalFile(env2js.OSFileSystem{}, settingsFilePath, "")).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Describe("Apply", func() {
+		It("should apply the overrides to the pristine copy in template mode", func() {
+			// Arrange
+			options := env2js.Options{File: settingsFilePath, Variable: "AppSettings", Template: true}
+			options.Environ = func() []string { return []string{"AppSettings_MyKey=Test1"} }
+			_, err := env2js.Apply(context.Background(), options)
+			Expect(err).NotTo(HaveOccurred())
+			// The variable is removed before the container restarts
+			options.Environ = func() []string { return []string{} }
+			// Act
+			_, err = env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadFile(settingsFilePath)).To(BeEquivalentTo("const AppSettings = {MyKey: 'MyValue'};"))
+		})
+
+		It("should not save the pristine copy in dry run", func() {
+			// Act
+			_, err := env2js.Apply(context.Background(), env2js.Options{File: settingsFilePath, Variable: "AppSettings", Template: true, DryRun: true})
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settingsFilePath + env2js.OriginalFileSuffix).NotTo(BeAnExistingFile())
+		})
+	})
+})
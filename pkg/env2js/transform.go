@@ -0,0 +1,205 @@
+package env2js
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	SettingsTransformsPathEnvKey string = "SETTINGS_TRANSFORMS_PATH"
+
+	// Separates the transforms from the variable name, eg : AppSettings_API_apiKey__b64__trim
+	TransformSuffixSeparator string = "__"
+)
+
+// Transformer converts a value before it is written in the settings file.
+// The argument is the text following the name in the chain, eg : /api for url-join:/api
+type Transformer interface {
+	Apply(value string, argument string) (string, error)
+}
+
+// TransformFunc adapts a function to the Transformer interface.
+type TransformFunc func(value string, argument string) (string, error)
+
+func (f TransformFunc) Apply(value string, argument string) (string, error) {
+	return f(value, argument)
+}
+
+// Transformers are transforms by name, eg : the Transforms option of the library.
+type Transformers map[string]Transformer
+
+// builtinTransforms are the transforms by name of the chains when the Transforms option is nil, see BuiltinTransforms.
+// The errors never include the value, which may be a secret.
+var builtinTransforms = Transformers{
+	"b64":      TransformFunc(DecodeBase64),
+	"base64":   TransformFunc(DecodeBase64),
+	"trim":     TransformFunc(Trim),
+	"json":     TransformFunc(DecodeJSON),
+	"url-join": TransformFunc(JoinURL),
+	"lower":    TransformFunc(func(value string, _ string) (string, error) { return strings.ToLower(value), nil }),
+	"upper":    TransformFunc(func(value string, _ string) (string, error) { return strings.ToUpper(value), nil }),
+}
+
+// BuiltinTransforms returns a copy of the built-in transforms, eg : to add transforms to the Transforms option.
+func BuiltinTransforms() Transformers {
+	return maps.Clone(builtinTransforms)
+}
+
+// DecodeBase64 decodes a standard or URL base64 value, padded or not.
+func DecodeBase64(value string, _ string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if decoded, err := encoding.DecodeString(value); err == nil {
+			return string(decoded), nil
+		}
+	}
+
+	return "", errors.New("invalid base64 value")
+}
+
+// Trim removes the leading and trailing white spaces, or the characters of the argument.
+func Trim(value string, cutset string) (string, error) {
+	if cutset == "" {
+		return strings.TrimSpace(value), nil
+	}
+
+	return strings.Trim(value, cutset), nil
+}
+
+// DecodeJSON decodes a JSON string, or reads the field of a JSON object given as argument, eg : json:credentials.password
+// The fields that are not strings are returned as JSON.
+func DecodeJSON(value string, fieldPath string) (string, error) {
+	var decoded any
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return "", errors.New("invalid JSON value")
+	}
+
+	if fieldPath != "" {
+		for _, field := range strings.Split(fieldPath, ".") {
+			object, ok := decoded.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("no field %s in the JSON value", fieldPath)
+			}
+			if decoded, ok = object[field]; !ok {
+				return "", fmt.Errorf("no field %s in the JSON value", fieldPath)
+			}
+		}
+	}
+
+	if text, ok := decoded.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(decoded)
+	return string(encoded), err
+}
+
+// JoinURL appends the path of the argument to the URL with a single slash between them.
+// Without argument, the trailing slashes of the URL are removed.
+func JoinURL(value string, path string) (string, error) {
+	value = strings.TrimRight(value, "/")
+	if path == "" {
+		return value, nil
+	}
+
+	return value + "/" + strings.TrimLeft(path, "/"), nil
+}
+
+// TransformStep is a transform of a chain, with its argument.
+type TransformStep struct {
+	Name     string
+	Argument string
+}
+
+// TransformChain applies its transforms in order.
+type TransformChain []TransformStep
+
+// ParseTransformChain parses the steps of a chain with the built-in transforms, eg : ["b64", "trim", "url-join:/api"]
+func ParseTransformChain(steps []string) (TransformChain, error) {
+	return builtinTransforms.ParseChain(steps)
+}
+
+// ParseChain parses the steps of a chain with the transforms, the built-in ones when nil.
+func (t Transformers) ParseChain(steps []string) (TransformChain, error) {
+	if t == nil {
+		t = builtinTransforms
+	}
+
+	chain := TransformChain{}
+	for _, step := range steps {
+		name, argument, _ := strings.Cut(strings.TrimSpace(step), ":")
+		if _, ok := t[name]; !ok {
+			return nil, fmt.Errorf("unknown transform %q, expected one of : %s", name, strings.Join(t.Names(), ", "))
+		}
+		chain = append(chain, TransformStep{Name: name, Argument: argument})
+	}
+
+	return chain, nil
+}
+
+// Names lists the names of the transforms, sorted.
+func (t Transformers) Names() []string {
+	names := []string{}
+	for name := range t {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Apply runs the built-in transforms of the chain on the value.
+func (c TransformChain) Apply(value string) (string, error) {
+	return builtinTransforms.Apply(c, value)
+}
+
+// Apply runs the transforms of the chain on the value, the built-in ones when nil.
+func (t Transformers) Apply(chain TransformChain, value string) (string, error) {
+	if t == nil {
+		t = builtinTransforms
+	}
+
+	for _, step := range chain {
+		transformer, ok := t[step.Name]
+		if !ok {
+			return "", fmt.Errorf("unknown transform %q", step.Name)
+		}
+		var err error
+		if value, err = transformer.Apply(value, step.Argument); err != nil {
+			return "", fmt.Errorf("%s : %w", step.Name, err)
+		}
+	}
+
+	return value, nil
+}
+
+// ReadTransformsFile reads the transform chains by environment variable name from a JSON file,
+// eg : {"API_apiKey": "b64 | trim"}. The keys are prefixed with the settings variable name when missing.
+// The chains are made of the transforms, the built-in ones when nil.
+func ReadTransformsFile(fsys FileSystem, transformsFilePath string, prefix string, transformers Transformers) (map[string]TransformChain, error) {
+	transformsBytes, err := fsys.ReadFile(transformsFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	chains := map[string]string{}
+	if err := json.Unmarshal(transformsBytes, &chains); err != nil {
+		return nil, fmt.Errorf("invalid transforms file %s : %w", transformsFilePath, err)
+	}
+
+	transforms := map[string]TransformChain{}
+	for key, chain := range chains {
+		if !strings.HasPrefix(key, prefix) {
+			key = prefix + key
+		}
+		if transforms[key], err = transformers.ParseChain(strings.Split(chain, "|")); err != nil {
+			return nil, fmt.Errorf("invalid transforms file %s : %s : %w", transformsFilePath, key, err)
+		}
+	}
+
+	return transforms, nil
+}
@@ -0,0 +1,154 @@
+package env2js_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Transform", func() {
+	DescribeTable("TransformChain",
+		func(steps []string, value string, expected string) {
+			chain, err := env2js.ParseTransformChain(steps)
+			Expect(err).NotTo(HaveOccurred())
+			transformed, err := chain.Apply(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transformed).To(Equal(expected))
+		},
+		Entry("base64", []string{"b64"}, "czNjcjN0", "s3cr3t"),
+		Entry("unpadded URL base64", []string{"base64"}, "PDw_Pz4-", "<<??>>"),
+		Entry("trim", []string{"trim"}, " value\n", "value"),
+		Entry("trim characters", []string{"trim:/"}, "/api/", "api"),
+		Entry("JSON string", []string{"json"}, `"a \"quoted\" value"`, `a "quoted" value`),
+		Entry("JSON field", []string{"json:credentials.password"}, `{"credentials": {"password": "s3cr3t"}}`, "s3cr3t"),
+		Entry("JSON object field", []string{"json:credentials"}, `{"credentials": {"port": 8080}}`, `{"port":8080}`),
+		Entry("url-join", []string{"url-join:/api/v2"}, "https://example.com/", "https://example.com/api/v2"),
+		Entry("url-join without path", []string{"url-join"}, "https://example.com//", "https://example.com"),
+		Entry("lower", []string{"lower"}, "Info", "info"),
+		Entry("upper", []string{"upper"}, "eu-west", "EU-WEST"),
+		Entry("chain", []string{"b64", "trim", "url-join"}, "IGh0dHBzOi8vZXhhbXBsZS5jb20vCg==", "https://example.com"),
+	)
+
+	DescribeTable("should report the errors without the value",
+		func(steps []string, value string, expectedError string) {
+			chain, _ := env2js.ParseTransformChain(steps)
+			_, err := chain.Apply(value)
+			Expect(err).To(MatchError(expectedError))
+		},
+		Entry("base64", []string{"b64"}, "s3cr3t!", "b64 : invalid base64 value"),
+		Entry("JSON", []string{"json"}, "s3cr3t", "json : invalid JSON value"),
+		Entry("JSON field", []string{"json:password"}, `{"pass": "s3cr3t"}`, "json : no field password in the JSON value"),
+	)
+
+	It("should reject the unknown transforms", func() {
+		_, err := env2js.ParseTransformChain([]string{"b64", "rot13"})
+		Expect(err).To(MatchError(`unknown transform "rot13", expected one of : b64, base64, json, lower, trim, upper, url-join`))
+	})
+
+	It("should apply the added transforms without changing the built-in ones", func() {
+		// Arrange
+		transforms := env2js.BuiltinTransforms()
+		transforms["reverse"] = env2js.TransformFunc(func(value string, _ string) (string, error) {
+			runes := []rune(value)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return string(runes), nil
+		})
+		chain, err := transforms.ParseChain([]string{"upper", "reverse"})
+		Expect(err).NotTo(HaveOccurred())
+		// Act
+		transformed, _ := transforms.Apply(chain, "abc")
+		// Assert
+		Expect(transformed).To(Equal("CBA"))
+		Expect(env2js.BuiltinTransforms()).NotTo(HaveKey("reverse"))
+		_, err = env2js.ParseTransformChain([]string{"reverse"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("Overrides", func() {
+		It("should apply the transforms of the variable suffix", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_API_apiKey__b64__trim=czNjcjN0Cg==", "AppSettings_API__private=true"})
+			// Act
+			value, ok := overrides.Lookup([]string{"API", "apiKey"})
+			_, private := overrides.Lookup([]string{"API"})
+			// Assert
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("s3cr3t"))
+			Expect(private).To(BeFalse())
+			Expect(overrides.Consumed("AppSettings_API_apiKey")).To(BeTrue())
+			Expect(overrides.Unused()).To(Equal([]string{"AppSettings_API__private"}))
+		})
+
+		It("should apply the transforms of the mapping after the suffix ones", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_level__b64=SU5GTw=="})
+			overrides.Transforms = map[string]env2js.TransformChain{"AppSettings_level": {{Name: "lower"}}}
+			// Act
+			value, _ := overrides.Lookup([]string{"level"})
+			// Assert
+			Expect(value).To(Equal("info"))
+		})
+
+		It("should name the key of the values that cannot be transformed", func() {
+			// Arrange
+			overrides := env2js.NewOverrides("AppSettings", []string{"AppSettings_API_apiKey__b64=s3cr3t!"})
+			// Act
+			_, ok := overrides.Lookup([]string{"API", "apiKey"})
+			// Assert
+			Expect(ok).To(BeFalse())
+			Expect(overrides.Err()).To(MatchError("cannot transform AppSettings_API_apiKey : b64 : invalid base64 value"))
+		})
+	})
+
+	Describe("ReadTransformsFile", func() {
+		It("should prefix the keys", func() {
+			// Arrange
+			transformsFilePath := filepath.Join(GinkgoT().TempDir(), "transforms.json")
+			Expect(os.WriteFile(transformsFilePath, []byte(`{"API_apiRoot": "trim | url-join:/api", "AppSettings_level": "lower"}`), 0o644)).To(Succeed())
+			// Act
+			transforms, err := env2js.ReadTransformsFile(env2js.OSFileSystem{}, transformsFilePath, "AppSettings_", nil)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transforms).To(Equal(map[string]env2js.TransformChain{
+				"AppSettings_API_apiRoot": {{Name: "trim"}, {Name: "url-join", Argument: "/api"}},
+				"AppSettings_level":       {{Name: "lower"}},
+			}))
+		})
+
+		It("should reject the unknown transforms", func() {
+			// Arrange
+			transformsFilePath := filepath.Join(GinkgoT().TempDir(), "transforms.json")
+			Expect(os.WriteFile(transformsFilePath, []byte(`{"level": "lowercase"}`), 0o644)).To(Succeed())
+			// Act
+			_, err := env2js.ReadTransformsFile(env2js.OSFileSystem{}, transformsFilePath, "AppSettings_", nil)
+			// Assert
+			Expect(err).To(MatchError(HavePrefix("invalid transforms file " + transformsFilePath + " : AppSettings_level : unknown transform \"lowercase\"")))
+		})
+	})
+
+	Describe("Transform", func() {
+		It("should transform the values before writing them", func() {
+			// Arrange
+			logs := new(LogRecorder)
+			transformsFilePath := filepath.Join(GinkgoT().TempDir(), "transforms.json")
+			Expect(os.WriteFile(transformsFilePath, []byte(`{"API_apiRoot": "url-join:/api/v2"}`), 0o644)).To(Succeed())
+			options := env2js.Options{Variable: "AppSettings", TransformsFile: transformsFilePath, Logger: slog.New(logs), Environ: func() []string {
+				return []string{"AppSettings_API_apiRoot=https://example.com/", "AppSettings_title__b64=T3JkZXJz"}
+			}}
+			// Act
+			output, _, err := env2js.Transform([]byte("const AppSettings = {title: 'App', API: {apiRoot: 'url/server/app'}};"), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("const AppSettings = {title: 'Orders', API: {apiRoot: 'https://example.com/api/v2'}};"))
+			Expect(logs.Warnings).To(BeEmpty())
+		})
+	})
+})
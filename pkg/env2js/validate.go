@@ -0,0 +1,459 @@
+package env2js
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tdewolff/parse/v2/js"
+)
+
+const (
+	SettingsSchemaPathEnvKey string = "SETTINGS_SCHEMA_PATH"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Violation is a setting not satisfying its schema or constraints.
+type Violation struct {
+	Key     string
+	Message string
+}
+
+// ReadSchemaFile reads a JSON Schema file.
+func ReadSchemaFile(fsys FileSystem, schemaFilePath string) (*Schema, error) {
+	schemaBytes, err := fsys.ReadFile(schemaFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{}
+	if err := json.Unmarshal(schemaBytes, schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema %s : %w", schemaFilePath, err)
+	}
+
+	return schema, nil
+}
+
+// ParseConstraint parses inline constraints, eg : "format=url", "enum=debug|info|warn|error" or "min=1 max=300".
+// Values containing spaces are double quoted : pattern="^a b$"
+func ParseConstraint(text string) (*Schema, error) {
+	return parseConstraintTokens(splitConstraint(text))
+}
+
+func parseConstraintTokens(tokens []string) (*Schema, error) {
+	schema := &Schema{}
+	for _, token := range tokens {
+		name, value, _ := strings.Cut(token, "=")
+		var err error
+		switch name {
+		case "type":
+			schema.Type = value
+		case "format":
+			schema.Format = value
+		case "pattern":
+			schema.Pattern = value
+		case "enum":
+			for _, item := range strings.Split(value, "|") {
+				schema.Enum = append(schema.Enum, item)
+			}
+		case "min", "minimum":
+			schema.Minimum, err = parseFloatConstraint(name, value)
+		case "max", "maximum":
+			schema.Maximum, err = parseFloatConstraint(name, value)
+		case "minLength":
+			schema.MinLength, err = parseIntConstraint(name, value)
+		case "maxLength":
+			schema.MaxLength, err = parseIntConstraint(name, value)
+		default:
+			err = fmt.Errorf("unknown constraint %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+// ParseConstraintFlags parses the -constraint flags, eg : AppSettings_timeout=min=1 max=300
+func ParseConstraintFlags(constraintFlags []string) (map[string]*Schema, error) {
+	constraints := map[string]*Schema{}
+	for _, constraintFlag := range constraintFlags {
+		key, text, ok := strings.Cut(constraintFlag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid constraint %q, expected KEY=constraints", constraintFlag)
+		}
+		schema, err := ParseConstraint(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint for %s : %w", key, err)
+		}
+		constraints[key] = schema
+	}
+
+	return constraints, nil
+}
+
+func splitConstraint(text string) []string {
+	tokens := []string{}
+	token := strings.Builder{}
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case (r == ' ' || r == '\t') && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens
+}
+
+func parseFloatConstraint(name string, value string) (*float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("constraint %s expects a number, got %q", name, value)
+	}
+
+	return &number, nil
+}
+
+func parseIntConstraint(name string, value string) (*int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("constraint %s expects an integer, got %q", name, value)
+	}
+
+	return &number, nil
+}
+
+// ValidateSettings evaluates the patched settings object against the schema, and every setting against
+// the constraints of its environment variable name. The schema can be nil.
+// The values of the secret settings are masked in the messages, isSecret can be nil.
+func ValidateSettings(object *js.ObjectExpr, prefix string, schema *Schema, constraints map[string]*Schema, isSecret func(key string) bool) []Violation {
+	validator := &settingsValidator{Prefix: prefix, Constraints: constraints, IsSecret: isSecret, Violations: []Violation{}}
+	validator.validate(object, []string{}, schema)
+	return validator.Violations
+}
+
+// ValidateConfigFile validates the patched settings object against the schema file, the inline constraints
+// and the annotations of the properties. The inline constraints take precedence over the annotations.
+func ValidateConfigFile(ast *js.AST, w *Walker, options Options) error {
+	if options.SchemaFile == "" && len(options.Constraints) == 0 && len(w.Annotations) == 0 {
+		return nil
+	}
+
+	var schema *Schema
+	if options.SchemaFile != "" {
+		var err error
+		if schema, err = ReadSchemaFile(fileSystemOrOS(options.FileSystem), options.SchemaFile); err != nil {
+			return WrapError(ErrMissingConfig, err)
+		}
+	}
+	constraints, err := ParseConstraintFlags(options.Constraints)
+	if err != nil {
+		return WrapError(ErrUsage, err)
+	}
+	for key, annotation := range w.Annotations {
+		if _, ok := constraints[key]; !ok {
+			constraints[key] = annotation.Constraint
+		}
+	}
+
+	object := SettingsObject(ast, w.SettingVariableName)
+	if object == nil {
+		return WrapError(ErrVariableNotFound, fmt.Errorf("settings variable %s is not assigned an object literal, it cannot be validated", w.SettingVariableName))
+	}
+
+	violations := []Violation{}
+	for _, requiredKey := range w.RequiredKeys() {
+		if !w.Overrides.Consumed(requiredKey) {
+			violations = append(violations, Violation{Key: requiredKey, Message: "required environment variable is not set"})
+		}
+	}
+	violations = append(violations, ValidateSettings(object, w.Overrides.Prefix, schema, constraints, w.IsSecret)...)
+
+	return WrapError(ErrValidation, ValidationError(violations))
+}
+
+// ValidationError lists every violation, one per line.
+func ValidationError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("%d setting(s) failed validation", len(violations))}
+	for _, violation := range violations {
+		lines = append(lines, "  "+violation.Key+" : "+violation.Message)
+	}
+
+	return fmt.Errorf("%s", strings.Join(lines, "\n"))
+}
+
+type settingsValidator struct {
+	Prefix      string
+	Constraints map[string]*Schema
+	IsSecret    func(key string) bool
+	Violations  []Violation
+}
+
+func (v *settingsValidator) key(path []string) string {
+	if len(path) == 0 {
+		return strings.TrimSuffix(v.Prefix, "_")
+	}
+
+	return v.Prefix + strings.Join(path, "_")
+}
+
+// display formats the value for the messages, masked for the secret settings and the values looking like secrets,
+// like Walker.Annotate.
+func (v *settingsValidator) display(path []string, value any) string {
+	text := ValueString(value)
+	return MaskValue(text, v.IsSecret != nil && v.IsSecret(v.key(path)) || LooksLikeSecret(text))
+}
+
+func (v *settingsValidator) fail(path []string, format string, args ...any) {
+	v.Violations = append(v.Violations, Violation{Key: v.key(path), Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *settingsValidator) validate(expr js.IExpr, path []string, schema *Schema) {
+	if constraint, ok := v.Constraints[v.key(path)]; ok {
+		v.validateValue(expr, path, constraint)
+	}
+	if schema != nil {
+		v.validateValue(expr, path, schema)
+	}
+
+	switch expr := expr.(type) {
+	case *js.ObjectExpr:
+		names := []string{}
+		for _, property := range expr.List {
+			if property.Spread || property.Name == nil || property.Name.IsComputed() {
+				continue
+			}
+			name := PropertyNameValue(property.Name)
+			names = append(names, name)
+
+			var propertySchema *Schema
+			if schema != nil {
+				propertySchema = schema.Properties[name]
+				if propertySchema == nil && schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+					v.fail(append(slices.Clone(path), property.Name.String()), "unexpected property")
+				}
+			}
+			v.validate(property.Value, append(slices.Clone(path), property.Name.String()), propertySchema)
+		}
+		if schema != nil {
+			for _, required := range schema.Required {
+				if !slices.Contains(names, required) {
+					v.fail(append(slices.Clone(path), required), "required property is missing")
+				}
+			}
+		}
+	case *js.ArrayExpr:
+		for i, item := range expr.List {
+			if item.Value == nil || item.Spread {
+				continue
+			}
+			var itemSchema *Schema
+			if schema != nil {
+				itemSchema = schema.Items
+			}
+			v.validate(item.Value, append(slices.Clone(path), "["+strconv.Itoa(i)+"]"), itemSchema)
+		}
+	}
+}
+
+// validateValue checks the keywords applying to the value itself, the nested values being validated by validate.
+func (v *settingsValidator) validateValue(expr js.IExpr, path []string, schema *Schema) {
+	value, known := ExprValue(expr)
+	if literal, ok := expr.(*js.LiteralExpr); ok && !known {
+		// A number or a boolean overridden with a value of another type, eg : timeout: abc
+		expected := schema.Type
+		if expected == "" {
+			expected = LiteralType(literal)
+		}
+		v.fail(path, "expected %s, got %s", expected, v.display(path, string(literal.Data)))
+		return
+	}
+	if !known {
+		return
+	}
+
+	if schema.Type != "" && !HasSchemaType(value, schema.Type) {
+		v.fail(path, "expected %s, got %s", schema.Type, ValueType(value))
+		return
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(item any) bool { return ValueString(item) == ValueString(value) }) {
+		enum := []string{}
+		for _, item := range schema.Enum {
+			enum = append(enum, ValueString(item))
+		}
+		v.fail(path, "must be one of %s (got %s)", strings.Join(enum, "|"), v.display(path, value))
+	}
+
+	switch value := value.(type) {
+	case float64:
+		if schema.Minimum != nil && value < *schema.Minimum {
+			v.fail(path, "must be >= %s (got %s)", ValueString(*schema.Minimum), v.display(path, value))
+		}
+		if schema.Maximum != nil && value > *schema.Maximum {
+			v.fail(path, "must be <= %s (got %s)", ValueString(*schema.Maximum), v.display(path, value))
+		}
+		if schema.ExclusiveMinimum != nil && value <= *schema.ExclusiveMinimum {
+			v.fail(path, "must be > %s (got %s)", ValueString(*schema.ExclusiveMinimum), v.display(path, value))
+		}
+		if schema.ExclusiveMaximum != nil && value >= *schema.ExclusiveMaximum {
+			v.fail(path, "must be < %s (got %s)", ValueString(*schema.ExclusiveMaximum), v.display(path, value))
+		}
+	case string:
+		if schema.MinLength != nil && len([]rune(value)) < *schema.MinLength {
+			v.fail(path, "must be at least %d characters long", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && len([]rune(value)) > *schema.MaxLength {
+			v.fail(path, "must be at most %d characters long", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if pattern, err := regexp.Compile(schema.Pattern); err != nil {
+				v.fail(path, "invalid pattern %s : %s", schema.Pattern, err)
+			} else if !pattern.MatchString(value) {
+				v.fail(path, "must match the pattern %s (got %s)", schema.Pattern, v.display(path, value))
+			}
+		}
+		if schema.Format != "" && !HasFormat(value, schema.Format) {
+			v.fail(path, "must be a valid %s (got %s)", schema.Format, v.display(path, value))
+		}
+	}
+}
+
+// ExprValue evaluates a literal of the settings object : string, float64, bool or nil for null.
+// Objects and arrays are returned as is, the other expressions and the literals whose data
+// does not match their type are unknown.
+func ExprValue(expr js.IExpr) (any, bool) {
+	switch expr := expr.(type) {
+	case *js.LiteralExpr:
+		switch LiteralType(expr) {
+		case "string":
+			return LiteralValue(expr), true
+		case "boolean":
+			// The data of an overridden boolean is the value of the environment variable
+			if data := string(expr.Data); data == "true" || data == "false" {
+				return data == "true", true
+			}
+		case "null":
+			return nil, true
+		case "number":
+			return parseNumber(string(expr.Data))
+		}
+	case *js.UnaryExpr:
+		literal, ok := expr.X.(*js.LiteralExpr)
+		if ok && expr.Op == js.NotToken && literal.TokenType == js.IntegerToken {
+			return string(literal.Data) == "0", true
+		}
+		if ok && expr.Op == js.NegToken && js.IsNumeric(literal.TokenType) {
+			if number, ok := parseNumber(string(literal.Data)); ok {
+				return -number.(float64), true
+			}
+		}
+	case *js.ObjectExpr, *js.ArrayExpr:
+		return expr, true
+	}
+
+	return nil, false
+}
+
+func parseNumber(data string) (any, bool) {
+	if number, err := strconv.ParseFloat(data, 64); err == nil {
+		return number, true
+	}
+	if number, err := strconv.ParseInt(data, 0, 64); err == nil {
+		return float64(number), true
+	}
+
+	return nil, false
+}
+
+// ValueType returns the JSON Schema type of a value returned by ExprValue.
+func ValueType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case *js.ObjectExpr:
+		return "object"
+	case *js.ArrayExpr:
+		return "array"
+	}
+
+	return "null"
+}
+
+// HasSchemaType tells whether the value is of the JSON Schema type, integer being a whole number.
+func HasSchemaType(value any, schemaType string) bool {
+	if schemaType == "integer" {
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	}
+
+	return ValueType(value) == schemaType
+}
+
+// ValueString formats a value the way it would be written in an environment variable.
+func ValueString(value any) string {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case nil:
+		return "null"
+	}
+
+	return fmt.Sprint(value)
+}
+
+// HasFormat checks the JSON Schema formats : uri (or url), email, hostname, ipv4, ipv6, date-time and uuid.
+// The other formats are accepted as is.
+func HasFormat(value string, format string) bool {
+	switch format {
+	case "uri", "url":
+		parsedURL, err := url.Parse(value)
+		return err == nil && parsedURL.Scheme != "" && (parsedURL.Host != "" || parsedURL.Opaque != "")
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "hostname":
+		return value != "" && len(value) <= 253 && !strings.ContainsAny(value, " /:@")
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && strings.Contains(value, ".")
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && strings.Contains(value, ":")
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	}
+
+	return true
+}
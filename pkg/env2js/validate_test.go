@@ -0,0 +1,192 @@
+package env2js_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Validate", func() {
+	validate := func(jsString string, schema *env2js.Schema, constraintFlags ...string) []env2js.Violation {
+		ast, err := js.Parse(parse.NewInputString(jsString), js.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		constraints, err := env2js.ParseConstraintFlags(constraintFlags)
+		Expect(err).NotTo(HaveOccurred())
+		return env2js.ValidateSettings(env2js.SettingsObject(ast, "AppSettings"), "AppSettings_", schema, constraints, func(key string) bool { return key == "AppSettings_secret" })
+	}
+
+	Describe("ParseConstraint", func() {
+		It("should parse the constraints separated by spaces", func() {
+			// Act
+			schema, err := env2js.ParseConstraint(`min=1 max=300 enum=debug|info pattern="^a b$"`)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*schema.Minimum).To(Equal(1.0))
+			Expect(*schema.Maximum).To(Equal(300.0))
+			Expect(schema.Enum).To(Equal([]any{"debug", "info"}))
+			Expect(schema.Pattern).To(Equal("^a b$"))
+		})
+
+		It("should fail on unknown constraints and invalid numbers", func() {
+			_, err := env2js.ParseConstraint("minimal=1")
+			Expect(err).To(MatchError(`unknown constraint "minimal"`))
+			_, err = env2js.ParseConstraint("min=one")
+			Expect(err).To(MatchError(`constraint min expects a number, got "one"`))
+		})
+
+		It("should fail on constraint flags without key", func() {
+			_, err := env2js.ParseConstraintFlags([]string{"min"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ValidateSettings", func() {
+		It("should list every violation with its environment variable name", func() {
+			// Arrange
+			schema := &env2js.Schema{
+				Type:     "object",
+				Required: []string{"API", "logLevel"},
+				Properties: map[string]*env2js.Schema{
+					"API": {Type: "object", Properties: map[string]*env2js.Schema{
+						"apiRoot": {Type: "string", Format: "uri"},
+						"timeout": {Type: "integer"},
+					}},
+					"MyArray": {Type: "array", Items: &env2js.Schema{Type: "string", MinLength: new(int)}},
+				},
+			}
+			// Act
+			violations := validate("const AppSettings = {API: {apiRoot: 'url/server/app', timeout: 1.5}, MyArray: ['a', 2]};", schema)
+			// Assert
+			Expect(violations).To(Equal([]env2js.Violation{
+				{Key: "AppSettings_API_apiRoot", Message: "must be a valid uri (got url/server/app)"},
+				{Key: "AppSettings_API_timeout", Message: "expected integer, got number"},
+				{Key: "AppSettings_MyArray_[1]", Message: "expected string, got number"},
+				{Key: "AppSettings_logLevel", Message: "required property is missing"},
+			}))
+		})
+
+		It("should apply the inline constraints of the environment variables", func() {
+			// Act
+			violations := validate("const AppSettings = {timeout: 0, logLevel: 'verbose', isServed: !0, secret: 'abc'};", nil,
+				"AppSettings_timeout=min=1 max=300", "AppSettings_logLevel=enum=debug|info", "AppSettings_isServed=type=boolean", "AppSettings_secret=pattern=^[0-9]+$")
+			// Assert
+			Expect(violations).To(Equal([]env2js.Violation{
+				{Key: "AppSettings_timeout", Message: "must be >= 1 (got 0)"},
+				{Key: "AppSettings_logLevel", Message: "must be one of debug|info (got verbose)"},
+				{Key: "AppSettings_secret", Message: "must match the pattern ^[0-9]+$ (got ********)"},
+			}))
+		})
+
+		It("should mask the values looking like secrets whatever their key", func() {
+			// Act
+			violations := validate("const AppSettings = {auth: 'eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjM0NTY3ODkwIn0.abc'};", nil, "AppSettings_auth=pattern=^[0-9]+$")
+			// Assert
+			Expect(violations).To(Equal([]env2js.Violation{{Key: "AppSettings_auth", Message: "must match the pattern ^[0-9]+$ (got ********)"}}))
+		})
+
+		It("should reject the additional properties when they are not allowed", func() {
+			// Arrange
+			additionalProperties := false
+			schema := &env2js.Schema{Type: "object", AdditionalProperties: &additionalProperties, Properties: map[string]*env2js.Schema{"known": {}}}
+			// Act
+			violations := validate("const AppSettings = {known: 1, unknown: 2};", schema)
+			// Assert
+			Expect(violations).To(Equal([]env2js.Violation{{Key: "AppSettings_unknown", Message: "unexpected property"}}))
+		})
+	})
+
+	Describe("HasFormat", func() {
+		DescribeTable("should check the formats",
+			func(value string, format string, expected bool) {
+				Expect(env2js.HasFormat(value, format)).To(Equal(expected))
+			},
+			Entry("url", "https://example.com/api", "url", true),
+			Entry("relative url", "/api", "url", false),
+			Entry("email", "admin@example.com", "email", true),
+			Entry("ipv4", "10.0.0.1", "ipv4", true),
+			Entry("ipv6 as ipv4", "::1", "ipv4", false),
+			Entry("date-time", "2024-01-02T03:04:05Z", "date-time", true),
+			Entry("uuid", "not-a-uuid", "uuid", false),
+			Entry("unknown format", "anything", "color", true),
+		)
+	})
+
+	Describe("ValidationError", func() {
+		It("should return nil without violation", func() {
+			Expect(env2js.ValidationError([]env2js.Violation{})).To(Succeed())
+		})
+
+		It("should list the violations one per line", func() {
+			err := env2js.ValidationError([]env2js.Violation{{Key: "AppSettings_timeout", Message: "must be >= 1 (got 0)"}})
+			Expect(err).To(MatchError("1 setting(s) failed validation\n  AppSettings_timeout : must be >= 1 (got 0)"))
+		})
+	})
+
+	Describe("Apply", func() {
+		var options env2js.Options
+		BeforeEach(func() {
+			folder := GinkgoT().TempDir()
+			options = env2js.Options{File: filepath.Join(folder, "main.js"), Variable: "AppSettings", SchemaFile: filepath.Join(folder, "schema.json")}
+			Expect(os.WriteFile(options.File, []byte("const AppSettings = {API: {timeout: 30}};"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(options.SchemaFile, []byte(`{"properties": {"API": {"properties": {"timeout": {"type": "integer", "maximum": 300}}}}}`), 0o644)).To(Succeed())
+		})
+
+		It("should abort without writing when the patched settings are invalid", func() {
+			// Arrange
+			options.Environ = func() []string { return []string{"AppSettings_API_timeout=600"} }
+			// Act
+			_, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrValidation))
+			Expect(os.ReadFile(options.File)).To(ContainSubstring("timeout: 30"))
+		})
+
+		It("should reject the overrides that do not match the type of the literal without validation rules", func() {
+			// Arrange
+			Expect(os.WriteFile(options.File, []byte("const AppSettings = {timeout: 30, retries: 3, flag: true, minified: !0};"), 0o644)).To(Succeed())
+			options.SchemaFile = ""
+			options.Environ = func() []string {
+				return []string{"AppSettings_timeout=1,evil:alert(1)", "AppSettings_retries=many", "AppSettings_flag=maybe", "AppSettings_minified=yes"}
+			}
+			// Act
+			_, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).To(MatchError(env2js.ErrValidation))
+			Expect(err).To(MatchError("cannot apply AppSettings_timeout : expected a number\n" +
+				"cannot apply AppSettings_retries : expected a number\n" +
+				"cannot apply AppSettings_flag : expected a boolean\n" +
+				"cannot apply AppSettings_minified : expected a boolean"))
+			Expect(os.ReadFile(options.File)).To(ContainSubstring("timeout: 30"))
+		})
+
+		It("should read the overridden booleans from their value", func() {
+			// Arrange
+			Expect(os.WriteFile(options.File, []byte("const AppSettings = {flag: true};"), 0o644)).To(Succeed())
+			options.SchemaFile = ""
+			options.Constraints = env2js.StringList{"AppSettings_flag=enum=true"}
+			options.Environ = func() []string { return []string{"AppSettings_flag=false"} }
+			// Act
+			_, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).To(MatchError(ContainSubstring("AppSettings_flag : must be one of true (got false)")))
+		})
+
+		It("should write the settings file when they are valid", func() {
+			// Arrange
+			options.Environ = func() []string { return []string{"AppSettings_API_timeout=60"} }
+			// Act
+			_, err := env2js.Apply(context.Background(), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadFile(options.File)).To(ContainSubstring("timeout: 60"))
+		})
+	})
+})
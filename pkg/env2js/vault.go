@@ -0,0 +1,162 @@
+package env2js
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	VaultAddrEnvKey         string = "VAULT_ADDR"
+	VaultTokenEnvKey        string = "VAULT_TOKEN"
+	VaultRoleIDEnvKey       string = "VAULT_ROLE_ID"
+	VaultSecretIDEnvKey     string = "VAULT_SECRET_ID"
+	VaultNamespaceEnvKey    string = "VAULT_NAMESPACE"
+	SettingsVaultPathEnvKey string = "SETTINGS_VAULT_PATH"
+	DefaultVaultMount       string = "secret"
+	DefaultVaultHTTPTimeout        = 10 * time.Second
+)
+
+// VaultConfig locates a KV v2 secret and holds the credentials to read it :
+// a token, or the role and secret ids of an AppRole.
+type VaultConfig struct {
+	Address   string
+	Namespace string
+	Mount     string
+	Path      string
+
+	Token    string
+	RoleID   string
+	SecretID string
+
+	Client *http.Client
+}
+
+// NewVaultConfig reads the location of the secret from the options and the credentials from the environment.
+func NewVaultConfig(options Options) VaultConfig {
+	options = options.withDefaults()
+	vault := VaultConfig{
+		Address:   options.VaultAddr,
+		Namespace: options.Getenv(VaultNamespaceEnvKey),
+		Mount:     options.VaultMount,
+		Path:      options.VaultPath,
+		Token:     options.Getenv(VaultTokenEnvKey),
+		RoleID:    options.Getenv(VaultRoleIDEnvKey),
+		SecretID:  options.Getenv(VaultSecretIDEnvKey),
+		Client:    options.HTTPClient,
+	}
+	if vault.Mount == "" {
+		vault.Mount = DefaultVaultMount
+	}
+	if vault.Client == nil {
+		vault.Client = &http.Client{Timeout: DefaultVaultHTTPTimeout}
+	}
+
+	return vault
+}
+
+// NewVaultSource reads the keys of a KV v2 secret.
+// The keys are named like the environment variables, with or without the settings variable prefix :
+// apiRoot, API_apiRoot or AppSettings_API_apiRoot. The values are never logged.
+func NewVaultSource(ctx context.Context, vault VaultConfig, prefix string) (*MapSource, error) {
+	path := strings.Trim(vault.Path, "/")
+	name := "vault " + vault.Mount + "/" + path
+	if vault.Address == "" {
+		return nil, WrapError(ErrMissingConfig, fmt.Errorf("%s : missing Vault address, see -vault-addr or %s", name, VaultAddrEnvKey))
+	}
+
+	token := vault.Token
+	if token == "" {
+		if vault.RoleID == "" || vault.SecretID == "" {
+			return nil, WrapError(ErrMissingConfig, fmt.Errorf("%s : missing credentials, set %s or both %s and %s", name, VaultTokenEnvKey, VaultRoleIDEnvKey, VaultSecretIDEnvKey))
+		}
+		var login struct {
+			Auth struct {
+				ClientToken string `json:"client_token"`
+			} `json:"auth"`
+		}
+		body, _ := json.Marshal(map[string]string{"role_id": vault.RoleID, "secret_id": vault.SecretID})
+		if err := vault.request(ctx, http.MethodPost, "auth/approle/login", "", body, &login); err != nil {
+			return nil, fmt.Errorf("%s : AppRole login : %w", name, err)
+		}
+		token = login.Auth.ClientToken
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := vault.request(ctx, http.MethodGet, vault.Mount+"/data/"+path, token, nil, &secret); err != nil {
+		return nil, fmt.Errorf("%s : %w", name, err)
+	}
+
+	environ := []string{}
+	for key, value := range secret.Data.Data {
+		if !strings.HasPrefix(key, prefix) {
+			key = prefix + key
+		}
+		environ = append(environ, key+"="+vaultValue(value))
+	}
+	source := NewEnvironSource(name, environ)
+	source.sensitive = true
+
+	return source, nil
+}
+
+// vaultValue formats a secret value like an environment variable : the strings as is, the other values as JSON.
+func vaultValue(value any) string {
+	if value, ok := value.(string); ok {
+		return value
+	}
+	valueBytes, _ := json.Marshal(value)
+	return string(valueBytes)
+}
+
+// request calls the Vault HTTP API and decodes the JSON response.
+// The errors only carry the status and the messages of Vault, never the response body.
+func (vault VaultConfig) request(ctx context.Context, method string, path string, token string, body []byte, response any) error {
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(vault.Address, "/")+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		request.Header.Set("X-Vault-Token", token)
+	}
+	if vault.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", vault.Namespace)
+	}
+
+	client := vault.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResponse, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBytes, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		var failure struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(responseBytes, &failure)
+		return fmt.Errorf("%s %s : %s", method, path, strings.TrimSpace(httpResponse.Status+" "+strings.Join(failure.Errors, ", ")))
+	}
+
+	if err := json.Unmarshal(responseBytes, response); err != nil {
+		return fmt.Errorf("%s %s : invalid response", method, path)
+	}
+
+	return nil
+}
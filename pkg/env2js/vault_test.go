@@ -0,0 +1,114 @@
+package env2js_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+// vaultStandIn serves a KV v2 secret at secret/app and an AppRole login.
+func vaultStandIn() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var login map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		if login["role_id"] != "role" || login["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"approle-token"}}`))
+	})
+	mux.HandleFunc("GET /v1/secret/data/app", func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("X-Vault-Token"); token != "root-token" && token != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"API_apiKey":"s3cr3t","AppSettings_API_timeout":30,"isServed":true},"metadata":{"version":3}}}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("Vault", func() {
+	var server *httptest.Server
+	BeforeEach(func() {
+		server = vaultStandIn()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewVaultSource", func() {
+		It("should read the secret with a token and prefix its keys", func() {
+			// Act
+			source, err := env2js.NewVaultSource(context.Background(), env2js.VaultConfig{Address: server.URL, Mount: "secret", Path: "app", Token: "root-token"}, "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.Name()).To(Equal("vault secret/app"))
+			Expect(source.Sensitive()).To(BeTrue())
+			Expect(source.List("AppSettings_")).To(Equal([]string{"AppSettings_API_apiKey", "AppSettings_API_timeout", "AppSettings_isServed"}))
+			for key, expected := range map[string]string{"AppSettings_API_apiKey": "s3cr3t", "AppSettings_API_timeout": "30", "AppSettings_isServed": "true"} {
+				value, _ := source.Lookup(key)
+				Expect(value).To(Equal(expected))
+			}
+		})
+
+		It("should log in with an AppRole", func() {
+			// Act
+			source, err := env2js.NewVaultSource(context.Background(), env2js.VaultConfig{Address: server.URL, Mount: "secret", Path: "/app/", RoleID: "role", SecretID: "secret"}, "AppSettings_")
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(source.List("AppSettings_")).To(HaveLen(3))
+		})
+
+		DescribeTable("should report the errors of Vault",
+			func(vault env2js.VaultConfig, expectedError string) {
+				vault.Mount = "secret"
+				vault.Path = "app"
+				if vault.Address != "" {
+					vault.Address = server.URL
+				}
+				_, err := env2js.NewVaultSource(context.Background(), vault, "AppSettings_")
+				Expect(err).To(MatchError(expectedError))
+			},
+			Entry("missing address", env2js.VaultConfig{Token: "root-token"}, "vault secret/app : missing Vault address, see -vault-addr or VAULT_ADDR"),
+			Entry("missing credentials", env2js.VaultConfig{Address: "-"}, "vault secret/app : missing credentials, set VAULT_TOKEN or both VAULT_ROLE_ID and VAULT_SECRET_ID"),
+			Entry("invalid token", env2js.VaultConfig{Address: "-", Token: "wrong"}, "vault secret/app : GET secret/data/app : 403 Forbidden permission denied"),
+			Entry("invalid AppRole", env2js.VaultConfig{Address: "-", RoleID: "role", SecretID: "wrong"}, "vault secret/app : AppRole login : POST auth/approle/login : 400 Bad Request invalid role or secret ID"),
+		)
+	})
+
+	Describe("Transform", func() {
+		It("should apply the values of Vault as secrets", func() {
+			// Arrange
+			options := env2js.Options{
+				Variable:   "AppSettings",
+				VaultAddr:  server.URL,
+				VaultMount: "secret",
+				VaultPath:  "app",
+				Environ:    func() []string { return []string{} },
+				Getenv:     func(key string) string { return map[string]string{env2js.VaultTokenEnvKey: "root-token"}[key] },
+				HTTPClient: server.Client(),
+			}
+			// Act
+			output, report, err := env2js.Transform([]byte("const AppSettings = {API: {apiKey: 'changeme', timeout: 10}};"), options)
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("const AppSettings = {API: {apiKey: 's3cr3t', timeout: 30}};"))
+			Expect(report.Changes).To(HaveLen(2))
+			for _, change := range report.Changes {
+				Expect(change.Secret).To(BeTrue())
+			}
+		})
+	})
+})
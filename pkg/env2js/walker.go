@@ -0,0 +1,334 @@
+package env2js
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// Setting is an overridable property of the settings object, with its value in the file.
+type Setting struct {
+	Key   string
+	Path  []string
+	Value string
+	Type  string
+
+	// Comment written above the property, see PropertyComments
+	Description string
+	// Annotated as secret, see Annotation
+	Secret bool
+}
+
+// Change is an environment value applied to a property.
+type Change struct {
+	Key      string
+	Path     []string
+	OldValue string
+	NewValue string
+	Secret   bool
+
+	// Source of the new value, see ValueSource
+	Origin string
+}
+
+type Walker struct {
+	CurrentPath         []string
+	SettingVariableName string
+	Overrides           *Overrides
+
+	// Whether the settings variable was found
+	Found bool
+
+	// Every overridable property encountered, and the overrides applied to them
+	Settings []Setting
+	Changes  []Change
+
+	// Annotations of the properties by environment variable name, see Annotate
+	Annotations map[string]*Annotation
+	// Glob patterns of the secret environment variable names, see MatchesSecretPattern
+	SecretPatterns []string
+}
+
+// Decimal, hexadecimal, octal or binary JavaScript number, eg : -1.5e3 or 0x1F
+var numberLiteralPattern = regexp.MustCompile(`^-?(0[xX][0-9a-fA-F]+|0[oO][0-7]+|0[bB][01]+|([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?)$`)
+
+// IsLiteralValue tells whether the value can be written in place of a literal of the type :
+// a JavaScript number for the numbers, true or false for the booleans. Any value fits a string.
+func IsLiteralValue(value string, valueType string) bool {
+	switch valueType {
+	case "number":
+		return numberLiteralPattern.MatchString(value)
+	case "boolean":
+		return value == "true" || value == "false"
+	}
+
+	return true
+}
+
+// Lookup records the current path as overridable and returns its overriding value.
+// The values that cannot replace the literal, eg : abc for a number, are reported by Overrides.Err and never applied.
+func (w *Walker) Lookup(value string, valueType string) (string, bool) {
+	key := w.Overrides.Key(w.CurrentPath)
+	w.Settings = append(w.Settings, Setting{Key: key, Path: slices.Clone(w.CurrentPath), Value: value, Type: valueType})
+
+	newValue, ok := w.Overrides.Lookup(w.CurrentPath)
+	if !ok && valueType == "string" {
+		newValue, ok = w.Overrides.Expand(key, value)
+	}
+	if ok && !IsLiteralValue(newValue, valueType) {
+		// The value is never quoted, it may be a secret
+		w.Overrides.errs = append(w.Overrides.errs, fmt.Errorf("cannot apply %s : expected a %s", key, valueType))
+		return "", false
+	}
+	if ok {
+		w.Changes = append(w.Changes, Change{Key: key, Path: slices.Clone(w.CurrentPath), OldValue: value, NewValue: newValue, Origin: w.Overrides.Origin(key)})
+	}
+
+	return newValue, ok
+}
+
+// Keys returns the environment variable names of every overridable property encountered.
+func (w *Walker) Keys() []string {
+	keys := []string{}
+	for _, setting := range w.Settings {
+		keys = append(keys, setting.Key)
+	}
+
+	return keys
+}
+
+func (w *Walker) Enter(n js.INode) js.IVisitor {
+	switch n := n.(type) {
+	case *js.BindingElement:
+		if n.Binding.String() != w.SettingVariableName {
+			return nil
+		}
+		w.Found = true
+	case *js.Property:
+		w.CurrentPath = append(w.CurrentPath, n.Name.String())
+		if valueExpression, ok := n.Value.(*js.LiteralExpr); ok {
+			if newStringValue, ok := w.Lookup(LiteralValue(valueExpression), LiteralType(valueExpression)); ok {
+				UpdateData(valueExpression, newStringValue)
+			}
+			w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
+			return nil
+		}
+		// Cas des propriété booléenne qui sont transformées en UnaryExpr par l'optimisation du build angular
+		// true => !0
+		// false => !1
+		if valueExpression, ok := n.Value.(*js.UnaryExpr); ok {
+			if literal, ok := valueExpression.X.(*js.LiteralExpr); ok && valueExpression.Op == js.NotToken && literal.TokenType == js.IntegerToken {
+				if newStringValue, ok := w.Lookup(strconv.FormatBool(string(literal.Data) == "0"), "boolean"); ok {
+					if newStringValue == "true" {
+						valueExpression.X = &js.LiteralExpr{Data: []byte("0"), TokenType: js.IntegerToken}
+					}
+					if newStringValue == "false" {
+						valueExpression.X = &js.LiteralExpr{Data: []byte("1"), TokenType: js.IntegerToken}
+					}
+				}
+			}
+			w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
+			return nil
+		}
+	case *js.ArrayExpr:
+		for i, item := range n.List {
+			if item.Value != nil {
+				if valueExpression, ok := item.Value.(*js.LiteralExpr); ok {
+					w.CurrentPath = append(w.CurrentPath, "["+fmt.Sprint(i)+"]")
+					if newStringValue, ok := w.Lookup(LiteralValue(valueExpression), LiteralType(valueExpression)); ok {
+						UpdateData(valueExpression, newStringValue)
+					}
+					w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
+				}
+			}
+		}
+	}
+	return w
+}
+
+func (w *Walker) Exit(n js.INode) {
+	switch n.(type) {
+	case *js.Property:
+		w.CurrentPath = w.CurrentPath[:len(w.CurrentPath)-1]
+	case *js.PropertyName:
+	}
+}
+
+// LiteralValue returns the value of a literal as it would be given in an environment variable.
+func LiteralValue(valueExpression *js.LiteralExpr) string {
+	if valueExpression.TokenType == js.StringToken && len(valueExpression.Data) >= 2 {
+		return UnquoteString(valueExpression.Data)
+	}
+
+	return string(valueExpression.Data)
+}
+
+// QuoteString writes a value as a single-quoted JavaScript string literal,
+// escaping the characters that would end the literal or the line.
+func QuoteString(value string) string {
+	quoted := strings.Builder{}
+	quoted.WriteByte('\'')
+	for _, r := range value {
+		switch r {
+		case '\'', '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteRune(r)
+		case '\n':
+			quoted.WriteString(`\n`)
+		case '\r':
+			quoted.WriteString(`\r`)
+		case '\u2028':
+			quoted.WriteString(`\u2028`)
+		case '\u2029':
+			quoted.WriteString(`\u2029`)
+		default:
+			quoted.WriteRune(r)
+		}
+	}
+	quoted.WriteByte('\'')
+
+	return quoted.String()
+}
+
+// UnquoteString returns the value of a JavaScript string literal, its quotes removed and its escape sequences resolved.
+// Invalid escape sequences are kept as written.
+func UnquoteString(literal []byte) string {
+	data := string(literal[1 : len(literal)-1])
+	if !strings.Contains(data, `\`) {
+		return data
+	}
+
+	value := strings.Builder{}
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' || i+1 == len(data) {
+			value.WriteByte(data[i])
+			continue
+		}
+		i++
+		switch c := data[i]; c {
+		case 'n':
+			value.WriteByte('\n')
+		case 'r':
+			value.WriteByte('\r')
+		case 't':
+			value.WriteByte('\t')
+		case 'b':
+			value.WriteByte('\b')
+		case 'f':
+			value.WriteByte('\f')
+		case 'v':
+			value.WriteByte('\v')
+		case '0':
+			value.WriteByte(0)
+		case '\n':
+			// Line continuation
+		case '\r':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+		case 'x', 'u':
+			r, size := unescapeCodePoint(data[i:])
+			if size == 0 {
+				value.WriteByte('\\')
+				value.WriteByte(c)
+				continue
+			}
+			value.WriteRune(r)
+			i += size - 1
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return value.String()
+}
+
+// unescapeCodePoint reads the code point of a \xHH, \uHHHH or \u{H...} escape sequence, starting at its letter.
+// The size is the number of bytes read, 0 when the sequence is invalid.
+func unescapeCodePoint(data string) (rune, int) {
+	digits, size := "", 0
+	switch {
+	case data[0] == 'x' && len(data) >= 3:
+		digits, size = data[1:3], 3
+	case strings.HasPrefix(data, "u{"):
+		end := strings.IndexByte(data, '}')
+		if end < 0 {
+			return 0, 0
+		}
+		digits, size = data[2:end], end+1
+	case data[0] == 'u' && len(data) >= 5:
+		digits, size = data[1:5], 5
+	default:
+		return 0, 0
+	}
+	codePoint, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, 0
+	}
+
+	return rune(codePoint), size
+}
+
+// LiteralType infers the type of a literal : string, boolean, number or null.
+func LiteralType(valueExpression *js.LiteralExpr) string {
+	switch valueExpression.TokenType {
+	case js.StringToken:
+		return "string"
+	case js.TrueToken, js.FalseToken:
+		return "boolean"
+	case js.DecimalToken, js.IntegerToken, js.BinaryToken, js.OctalToken, js.HexadecimalToken:
+		return "number"
+	case js.NullToken:
+		return "null"
+	}
+
+	return strings.ToLower(valueExpression.TokenType.String())
+}
+
+func UpdateData(valueExpression *js.LiteralExpr, newValue string) {
+	if valueExpression.TokenType.String() == "String" {
+		valueExpression.Data = []byte(QuoteString(newValue))
+		return
+	}
+
+	// Cas des booléens. Il y a TrueToken et FalseToken pour les valeurs de type booléen retourné par le parser
+	if valueExpression.TokenType.String() == "true" || valueExpression.TokenType.String() == "false" {
+		valueExpression.Data = []byte(newValue)
+		return
+	}
+
+	if valueExpression.TokenType.String() == "Decimal" {
+		valueExpression.Data = []byte(newValue)
+		return
+	}
+
+	if valueExpression.TokenType.String() == "Integer" {
+		valueExpression.Data = []byte(newValue)
+		return
+	}
+}
+
+// ParseJS parses the content of a JavaScript file.
+func ParseJS(jsBytes []byte) (*js.AST, error) {
+	input := parse.NewInput(bytes.NewReader(jsBytes))
+	return js.Parse(input, js.Options{})
+}
+
+// PropertyPath formats a property path as in JavaScript, eg : API.apiRoot or MyArray[0]
+func PropertyPath(path []string) string {
+	propertyPath := strings.Builder{}
+	for _, name := range path {
+		if propertyPath.Len() > 0 && !strings.HasPrefix(name, "[") {
+			propertyPath.WriteString(".")
+		}
+		propertyPath.WriteString(name)
+	}
+
+	return propertyPath.String()
+}
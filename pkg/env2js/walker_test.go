@@ -0,0 +1,166 @@
+package env2js_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+
+	// Local Module
+	"github.com/fleroy-isagri/env2js/pkg/env2js"
+)
+
+var _ = Describe("Walker", func() {
+	Describe("IVisitor - When calling the walk function", func() {
+		It("should erase the file key value with the new value with a string value", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=Test1"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 'MyValue1'};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: 'Test1'};"))
+		})
+
+		It("should erase the file key value with the new value with a boolean value", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=true"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: false};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: true};"))
+		})
+
+		It("should erase the file key value with the new value with a unaexpression boolean value for a newvalue as true", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=true"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: !1};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: !0};"))
+		})
+
+		It("should erase the file key value with the new value with a unaexpression boolean value for a newvalue as false", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=false"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: !0};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: !1};"))
+		})
+
+		It("should erase the file key value with the new value with an integer value", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=1"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 10};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: 1};"))
+		})
+
+		It("should erase the file key value with the new value with a decimal value", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyKey=1"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyKey: 0.5};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyKey: 1};"))
+		})
+
+		It("should show the current version with an array value", func() {
+			// Arrange
+			environ := []string{"AppSettings_MyArray_[0]=Test1", "AppSettings_MyArray_[1]=Test2"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {MyArray: ['MyValue1', 'MyValue2']};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {MyArray: ['Test1', 'Test2']};"))
+		})
+
+		It("should leave the unary expressions of other values untouched", func() {
+			// Arrange
+			environ := []string{"AppSettings_a=true"}
+			// Act
+			result := InterpretJSStringAsAst("const AppSettings = {a: !x, b: -1};", environ...)
+			// Assert
+			Expect(result).To(Equal("const AppSettings = {a: !x, b: -1};"))
+		})
+
+		It("should not do anything with an invalid binding element value", func() {
+			// Act
+			result := InterpretJSStringAsAst("const WrongBindingElement = {};")
+			// Assert
+			Expect(result).To(Equal("const WrongBindingElement = {};"))
+		})
+	})
+
+	DescribeTable("IsLiteralValue",
+		func(value string, valueType string, expected bool) {
+			Expect(env2js.IsLiteralValue(value, valueType)).To(Equal(expected))
+		},
+		Entry("integer", "30", "number", true),
+		Entry("negative decimal", "-1.5e3", "number", true),
+		Entry("leading dot", ".5", "number", true),
+		Entry("hexadecimal", "0x1F", "number", true),
+		Entry("word", "abc", "number", false),
+		Entry("injection", "1,evil:alert(1)", "number", false),
+		Entry("infinity", "Infinity", "number", false),
+		Entry("empty number", "", "number", false),
+		Entry("true", "true", "boolean", true),
+		Entry("false", "false", "boolean", true),
+		Entry("other boolean", "yes", "boolean", false),
+		Entry("any string", "1,evil:alert(1)", "string", true),
+	)
+
+	Describe("Lookup", func() {
+		It("should neither apply nor record the minified booleans overridden with another value", func() {
+			// Arrange
+			walker := &env2js.Walker{SettingVariableName: "AppSettings", Overrides: env2js.NewOverrides("AppSettings", []string{"AppSettings_f=yes", "AppSettings_t=false"})}
+			ast, _ := env2js.ParseJS([]byte("const AppSettings = {f: !0, t: !0};"))
+			// Act
+			js.Walk(walker, ast)
+			// Assert
+			Expect(walker.Overrides.Err()).To(MatchError("cannot apply AppSettings_f : expected a boolean"))
+			Expect(walker.Changes).To(HaveLen(1))
+			Expect(walker.Changes[0].Key).To(Equal("AppSettings_t"))
+			Expect(ast.JSString()).To(Equal("const AppSettings = {f: !0, t: !1};"))
+		})
+	})
+
+	DescribeTable("QuoteString",
+		func(value string, expected string) {
+			Expect(env2js.QuoteString(value)).To(Equal(expected))
+			Expect(env2js.UnquoteString([]byte(expected))).To(Equal(value))
+		},
+		Entry("plain value", "https://example.com/api", `'https://example.com/api'`),
+		Entry("quote", "it's", `'it\'s'`),
+		Entry("backslash", `C:\temp`, `'C:\\temp'`),
+		Entry("line breaks", "line1\nline2\r\n", `'line1\nline2\r\n'`),
+		Entry("line separators", "a\u2028b\u2029c", `'a\u2028b\u2029c'`),
+	)
+
+	DescribeTable("UnquoteString",
+		func(literal string, expected string) {
+			Expect(env2js.UnquoteString([]byte(literal))).To(Equal(expected))
+		},
+		Entry("double quotes", `"say \"hi\""`, `say "hi"`),
+		Entry("hexadecimal escapes", `'\x41\u0042\u{1F600}'`, "AB😀"),
+		Entry("line continuation", "'a\\\nb'", "ab"),
+		Entry("invalid escape", `'\xZZ'`, `\xZZ`),
+	)
+
+	Describe("PropertyPath", func() {
+		It("should join the properties with dots and keep the array indexes", func() {
+			Expect(env2js.PropertyPath([]string{"API", "hosts", "[1]", "name"})).To(Equal("API.hosts[1].name"))
+		})
+	})
+})
+
+////////////// HELPERS //////////////
+
+func InterpretJSStringAsAst(jsString string, environ ...string) string {
+	// Parse the JavaScript file
+	input := parse.NewInputString(jsString)
+	ast, _ := js.Parse(input, js.Options{})
+	// Analyse du code javascript et réalisation des modifications si nécessaire
+	js.Walk(&env2js.Walker{SettingVariableName: "AppSettings", Overrides: env2js.NewOverrides("AppSettings", environ)}, ast)
+	return ast.JSString()
+}
@@ -1,14 +1,168 @@
 package utils
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 )
 
+// Logs are written on the standard error, leaving the standard output to the commands output.
+
+const (
+	LogFormatText string = "text"
+	LogFormatJSON string = "json"
+)
+
+var LogFormats = []string{LogFormatText, LogFormatJSON}
+
+// Logger writes the logs, see Configure
+var Logger = slog.New(NewTextHandler(color.Error, slog.LevelInfo))
+
+// Options configure the logger from the command-line flags.
+type Options struct {
+	// text or json
+	Format string
+	// debug, info, warn or error
+	Level string
+	// Only log the errors
+	Quiet bool
+
+	// Output of the logs, the standard error by default
+	Output io.Writer
+}
+
+// Configure replaces the logger. On the standard error, the colors are disabled
+// when NO_COLOR is set or when it is not a terminal.
+func Configure(options Options) error {
+	level := slog.LevelInfo
+	if options.Level != "" {
+		if err := level.UnmarshalText([]byte(options.Level)); err != nil {
+			return fmt.Errorf("unknown log level %q, expected one of : debug, info, warn, error", options.Level)
+		}
+	}
+	if options.Quiet {
+		level = slog.LevelError
+	}
+
+	output := options.Output
+	if output == nil {
+		output = color.Error
+		fd := os.Stderr.Fd()
+		color.NoColor = os.Getenv("NO_COLOR") != "" || !(isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd))
+	}
+
+	switch options.Format {
+	case "", LogFormatText:
+		Logger = slog.New(NewTextHandler(output, level))
+	case LogFormatJSON:
+		Logger = slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: level, ReplaceAttr: plainMessage}))
+	default:
+		return fmt.Errorf("unknown log format %q, expected one of : %s", options.Format, strings.Join(LogFormats, ", "))
+	}
+
+	return nil
+}
+
+// plainMessage removes the decorations of the messages, eg : "✓ file: main.js 🎉" becomes "file: main.js"
+func plainMessage(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.MessageKey {
+		message := strings.TrimLeftFunc(attr.Value.String(), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		attr.Value = slog.StringValue(strings.TrimSuffix(message, " 🎉"))
+	}
+
+	return attr
+}
+
+// TextHandler writes a line per record, colored by level, followed by its key=value fields.
+type TextHandler struct {
+	output io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	group  string
+	mutex  *sync.Mutex
+}
+
+func NewTextHandler(output io.Writer, level slog.Leveler) *TextHandler {
+	return &TextHandler{output: output, level: level, mutex: &sync.Mutex{}}
+}
+
+func (h *TextHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *TextHandler) Handle(_ context.Context, record slog.Record) error {
+	line := strings.Builder{}
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString(color.New(color.Bold, color.FgRed).Sprint(record.Message))
+	case record.Level >= slog.LevelWarn:
+		line.WriteString(color.New(color.FgYellow).Sprint(record.Message))
+	case record.Level >= slog.LevelInfo:
+		line.WriteString(color.New(color.FgGreen).Sprint(record.Message))
+	default:
+		line.WriteString(color.New(color.Faint).Sprint(record.Message))
+	}
+
+	attrs := slices.Clone(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, h.prefixed(attr))
+		return true
+	})
+	for _, attr := range attrs {
+		value := attr.Value.Resolve().String()
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		line.WriteString(color.New(color.Faint).Sprint(" " + attr.Key + "=" + value))
+	}
+	line.WriteString("\n")
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	_, err := io.WriteString(h.output, line.String())
+	return err
+}
+
+func (h *TextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = slices.Clone(h.attrs)
+	for _, attr := range attrs {
+		handler.attrs = append(handler.attrs, h.prefixed(attr))
+	}
+
+	return &handler
+}
+
+func (h *TextHandler) WithGroup(name string) slog.Handler {
+	handler := *h
+	handler.group = h.group + name + "."
+
+	return &handler
+}
+
+func (h *TextHandler) prefixed(attr slog.Attr) slog.Attr {
+	attr.Key = h.group + attr.Key
+	return attr
+}
+
 func LogError(title string, log string) {
-	color.New(color.Bold).Add(color.FgRed).Println(title + " : " + log)
+	Logger.Error(title + " : " + log)
 }
 
 func LogSuccess(title string, log string) {
-	color.New(color.Bold).Print(title)
-	color.Green(log)
+	Logger.Info(title + log)
+}
+
+func LogWarning(title string, log string) {
+	Logger.Warn(title + log)
 }
@@ -1,6 +1,10 @@
 package utils_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -9,6 +13,10 @@ import (
 )
 
 var _ = Describe("Logs", func() {
+	AfterEach(func() {
+		Expect(Configure(Options{Output: GinkgoWriter})).To(Succeed())
+	})
+
 	It("should not panic when calling the LogError function", func() {
 		Expect(func() { LogError("Test", "Error") }).NotTo(Panic())
 	})
@@ -16,4 +24,70 @@ var _ = Describe("Logs", func() {
 	It("should not panic when calling the LogSucess function", func() {
 		Expect(func() { LogSuccess("Test : ", "Sucess") }).NotTo(Panic())
 	})
+
+	It("should not panic when calling the LogWarning function", func() {
+		Expect(func() { LogWarning("Test : ", "Warning") }).NotTo(Panic())
+	})
+
+	Describe("Configure", func() {
+		It("should write the text lines with their fields", func() {
+			// Arrange
+			output := new(bytes.Buffer)
+			Expect(Configure(Options{Output: output})).To(Succeed())
+			// Act
+			LogSuccess("✓ file: ", "main.js")
+			Logger.Info("Applied AppSettings_title", "path", "title", "value", "My app", "origin", "")
+			// Assert
+			Expect(output.String()).To(Equal("✓ file: main.js\nApplied AppSettings_title path=title value=\"My app\" origin=\"\"\n"))
+		})
+
+		It("should write JSON lines without the decorations", func() {
+			// Arrange
+			output := new(bytes.Buffer)
+			Expect(Configure(Options{Format: LogFormatJSON, Output: output})).To(Succeed())
+			// Act
+			LogSuccess("🎉 Successfuly updated : ", "main.js 🎉")
+			LogWarning("⚠ Unused environment variable : ", "AppSettings_titel")
+			// Assert
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+			records := []map[string]any{}
+			for _, line := range lines {
+				record := map[string]any{}
+				Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+				records = append(records, record)
+			}
+			Expect(records[0]).To(HaveKeyWithValue("level", "INFO"))
+			Expect(records[0]).To(HaveKeyWithValue("msg", "Successfuly updated : main.js"))
+			Expect(records[1]).To(HaveKeyWithValue("level", "WARN"))
+			Expect(records[1]).To(HaveKeyWithValue("msg", "Unused environment variable : AppSettings_titel"))
+		})
+
+		It("should filter the records below the level", func() {
+			// Arrange
+			output := new(bytes.Buffer)
+			Expect(Configure(Options{Level: "warn", Output: output})).To(Succeed())
+			// Act
+			LogSuccess("✓ file: ", "main.js")
+			LogWarning("⚠ warning", "")
+			// Assert
+			Expect(output.String()).To(Equal("⚠ warning\n"))
+		})
+
+		It("should only log the errors when quiet", func() {
+			// Arrange
+			output := new(bytes.Buffer)
+			Expect(Configure(Options{Level: "debug", Quiet: true, Output: output})).To(Succeed())
+			// Act
+			LogWarning("⚠ warning", "")
+			LogError("❌ ERROR", "failure")
+			// Assert
+			Expect(output.String()).To(Equal("❌ ERROR : failure\n"))
+		})
+
+		It("should reject the unknown formats and levels", func() {
+			Expect(Configure(Options{Format: "xml"})).To(MatchError("unknown log format \"xml\", expected one of : text, json"))
+			Expect(Configure(Options{Level: "verbose"})).To(MatchError("unknown log level \"verbose\", expected one of : debug, info, warn, error"))
+		})
+	})
 })